@@ -34,13 +34,9 @@ func GenerateWrapper(this js.Value, args []js.Value) interface{} {
 	}
 
 	// Verifica linguagens
-	languages := config.Meta.Langs
+	languages := config.Languages()
 	if len(languages) == 0 {
-		if config.Meta.Lang != "" {
-			languages = []string{config.Meta.Lang}
-		} else {
-			return "// Error: No language specified in 'meta.lang' or 'meta.langs'"
-		}
+		return "// Error: No language specified in 'meta.lang' or 'meta.langs'"
 	}
 
 	// Gera o código para todas as linguagens pedidas e concatena
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+// Arquivo padrão do cache incremental
+const defaultCacheFile = ".autotest-cache"
+
+// Formato do arquivo de cache; mudar invalida caches antigos
+const cacheFormat = 1
+
+// cacheEntry registra as entradas que produziram um arquivo de teste.
+type cacheEntry struct {
+	Spec      string `json:"spec"`
+	Generator string `json:"generator"`
+	Template  string `json:"template"`
+	Renderer  int    `json:"renderer"`
+	Framework string `json:"framework,omitempty"`
+}
+
+// cache guarda, por arquivo gerado, o hash da spec, a versão do gerador,
+// o hash do template, a versão do renderizador e o framework usados na última geração.
+type cache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	dirty   bool
+}
+
+type cacheFile struct {
+	Format  int                   `json:"format"`
+	Entries map[string]cacheEntry `json:"entries"`
+}
+
+// loadCache lê o cache do disco. Um cache ausente ou corrompido
+// simplesmente começa vazio e será regravado no final da execução.
+func loadCache(path string) *cache {
+	c := &cache{path: path, entries: map[string]cacheEntry{}}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+
+	var f cacheFile
+	if err := json.Unmarshal(data, &f); err != nil || f.Format != cacheFormat {
+		return c
+	}
+	if f.Entries != nil {
+		c.entries = f.Entries
+	}
+	return c
+}
+
+// Fresh indica se o arquivo em "path" ainda corresponde às entradas atuais.
+// O arquivo também precisa existir no disco, senão é regenerado.
+func (c *cache) Fresh(path string, entry cacheEntry) bool {
+	c.mu.Lock()
+	cached, ok := c.entries[filepath.ToSlash(path)]
+	c.mu.Unlock()
+
+	if !ok || cached != entry {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (c *cache) Put(path string, entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[filepath.ToSlash(path)] = entry
+	c.dirty = true
+}
+
+// Save grava o cache de forma atômica (arquivo temporário + rename).
+func (c *cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(cacheFile{Format: cacheFormat, Entries: c.entries}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return errors.Join(err, os.Remove(tmp))
+	}
+	c.dirty = false
+	return nil
+}
+
+// newCacheEntry calcula a entrada de cache para uma spec em uma linguagem.
+// O framework resolvido entra na chave porque -framework muda a saída sem mudar a spec.
+func newCacheEntry(specData []byte, lang string, meta core.MetaInfo) cacheEntry {
+	source, _ := core.TemplateSource(lang)
+	stack, _ := meta.StackFor(lang)
+	return cacheEntry{
+		Spec:      hashOf(specData),
+		Generator: core.Version,
+		Template:  hashOf([]byte(source)),
+		Renderer:  core.RendererVersion,
+		Framework: stack.String(),
+	}
+}
+
+func hashOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
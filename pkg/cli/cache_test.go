@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+func TestCacheFresh(t *testing.T) {
+	spec := []byte(`{"target": {"class_name": "Svc", "method_name": "run"}}`)
+	base := newCacheEntry(spec, "go", core.MetaInfo{})
+
+	tests := []struct {
+		name   string
+		change func(e cacheEntry) cacheEntry
+		remove bool
+		want   bool
+	}{
+		{"same inputs", func(e cacheEntry) cacheEntry { return e }, false, true},
+		{"spec changed", func(e cacheEntry) cacheEntry {
+			return newCacheEntry(append(spec, ' '), "go", core.MetaInfo{})
+		}, false, false},
+		{"generator changed", func(e cacheEntry) cacheEntry { e.Generator = "0.0.0"; return e }, false, false},
+		{"template changed", func(e cacheEntry) cacheEntry { e.Template = hashOf([]byte("x")); return e }, false, false},
+		{"renderer changed", func(e cacheEntry) cacheEntry { e.Renderer++; return e }, false, false},
+		{"framework changed", func(e cacheEntry) cacheEntry {
+			return newCacheEntry(spec, "go", core.MetaInfo{Frameworks: map[string]string{"go": "gomock"}})
+		}, false, false},
+		{"output removed", func(e cacheEntry) cacheEntry { return e }, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			out := filepath.Join(dir, "svc_test.go")
+			if err := os.WriteFile(out, []byte("package svc"), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			c := loadCache(filepath.Join(dir, defaultCacheFile))
+			c.Put(out, base)
+			if err := c.Save(); err != nil {
+				t.Fatal(err)
+			}
+			if tt.remove {
+				if err := os.Remove(out); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Relê do disco para cobrir também a ida e volta do arquivo
+			c = loadCache(filepath.Join(dir, defaultCacheFile))
+			if got := c.Fresh(out, tt.change(base)); got != tt.want {
+				t.Errorf("Fresh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadCacheStartsEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"missing file", ""},
+		{"corrupted file", "{not json"},
+		{"old format", `{"format": 0, "entries": {"a_test.go": {"spec": "x"}}}`},
+		{"no entries", `{"format": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), defaultCacheFile)
+			if tt.data != "" {
+				if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c := loadCache(path)
+			if c.entries == nil || len(c.entries) != 0 {
+				t.Errorf("entries = %v, want empty map", c.entries)
+			}
+			c.Put("a_test.go", cacheEntry{Spec: "x"})
+			if err := c.Save(); err != nil {
+				t.Fatalf("Save() = %v", err)
+			}
+			if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+				t.Errorf("temporary file left behind: %v", err)
+			}
+		})
+	}
+}
+
+func TestCacheSaveOnlyWhenDirty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), defaultCacheFile)
+	c := loadCache(path)
+	if err := c.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("clean cache was written: %v", err)
+	}
+}
@@ -0,0 +1,464 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+// Nome usado quando a spec chega via stdin (-file -)
+const stdinName = "<stdin>"
+
+// Status de cada arquivo no final da execução
+type Status string
+
+const (
+	StatusGenerated Status = "generated"
+	StatusUnchanged Status = "unchanged"
+	StatusSkipped   Status = "skipped"
+	StatusError     Status = "error"
+)
+
+// GeneratedFile representa um arquivo de teste pronto para ser emitido.
+type GeneratedFile struct {
+	Spec   string
+	Lang   string
+	Path   string
+	Code   string
+	Status Status
+	Err    error // Motivo quando Status == StatusSkipped ou StatusError
+
+	Duration time.Duration
+
+	entry    cacheEntry
+	exitCode int // Exit code associado a Err
+}
+
+// Run executa a CLI com os argumentos informados e devolve o exit code.
+// Recebe stdin/stdout/stderr explicitamente para permitir uso em pipelines.
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	start := time.Now()
+
+	// Subcomandos
+	if len(args) > 0 && args[0] == "watch" {
+		return runWatch(args[1:], stdout, stderr)
+	}
+
+	flags := flag.NewFlagSet("autotest", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	// Flags
+	fileFlag := flags.String("file", "", "Path to JSON spec file (supports wildcards like specs/*.json, or - for stdin)")
+	outFlag := flags.String("out", "", "Output filename (Only used in simple mode)")
+	templatesFlag := flags.String("templates", "", "Directory with custom <lang>.tmpl templates")
+
+	// Simple Mode Flags
+	langFlag := flags.String("lang", "", "Language")
+	classFlag := flags.String("class", "", "Class Name")
+
+	// Streaming Flags
+	printFlag := flags.Bool("print", false, "Print generated code to stdout instead of writing files")
+	tarFlag := flags.Bool("tar", false, "Write generated files to stdout as a tar stream")
+
+	// Batch Mode Flags
+	jobsFlag := flags.Int("j", runtime.NumCPU(), "Number of specs processed in parallel")
+	cacheFlag := flags.String("cache", defaultCacheFile, "Incremental cache file (empty disables the cache)")
+	forceFlag := flags.Bool("force", false, "Regenerate every file, ignoring the cache")
+
+	// Framework Flags
+	var frameworks frameworkFlag
+	flags.Var(&frameworks, "framework", "Test framework, for all languages (nunit+moq) or per language (csharp=nunit,ts=vitest)")
+	flags.Func("style", "Deprecated: same as -framework scala=<style>", func(style string) error {
+		return frameworks.Set("scala=" + style)
+	})
+	configFlag := flags.String("config", defaultConfigFile, "Project config file with default frameworks (empty disables it)")
+
+	// Report Flags
+	reportFlag := flags.String("report", "", "Write a machine-readable run report: json or junit")
+	reportFileFlag := flags.String("report-file", "", "Report destination (default: stdout)")
+
+	if err := flags.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if *jobsFlag < 1 {
+		*jobsFlag = 1
+	}
+
+	if *reportFlag != "" && *reportFlag != reportJSON && *reportFlag != reportJUnit {
+		fmt.Fprintf(stderr, "❌ Unknown report format: %s (use %s or %s)\n", *reportFlag, reportJSON, reportJUnit)
+		return ExitUsage
+	}
+	reportToStdout := *reportFlag != "" && *reportFileFlag == ""
+	if reportToStdout && (*printFlag || *tarFlag) {
+		fmt.Fprintln(stderr, "❌ -report needs -report-file when combined with -print or -tar")
+		return ExitUsage
+	}
+
+	if *templatesFlag != "" {
+		if err := loadTemplateDir(*templatesFlag); err != nil {
+			fmt.Fprintf(stderr, "❌ %v\n", err)
+			return ExitInvalidInput
+		}
+	}
+
+	defaults, err := loadConfig(*configFlag, isSet(flags, "config"))
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ %v\n", err)
+		return ExitInvalidInput
+	}
+
+	// Configura pasta de saída padrão
+	const outputDir = "test"
+
+	// Quando o stdout carrega o código gerado, as mensagens vão para o stderr
+	logOut := stdout
+	var out sink
+	switch {
+	case *tarFlag:
+		out = newTarSink(stdout)
+		logOut = stderr
+	case *printFlag:
+		out = newSectionSink(stdout)
+		logOut = stderr
+	default:
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			fmt.Fprintf(stderr, "❌ Error creating directory: %v\n", err)
+			return ExitWriteError
+		}
+		out = diskSink{}
+		if reportToStdout {
+			logOut = stderr
+		}
+	}
+
+	// --- MODO 1: ARQUIVO(S) DE ESPECIFICAÇÃO ---
+	if *fileFlag != "" {
+		files, err := expandSpecs(*fileFlag)
+		if err != nil {
+			fmt.Fprintf(stderr, "❌ %v\n", err)
+			return ExitInvalidInput
+		}
+
+		gen := &generator{stdin: stdin, outputDir: outputDir, force: *forceFlag, frameworks: &frameworks, defaults: defaults}
+
+		// O cache só faz sentido quando os arquivos vão para o disco
+		if *cacheFlag != "" && !*printFlag && !*tarFlag {
+			gen.cache = loadCache(*cacheFlag)
+		}
+		if *reportFlag != "" {
+			gen.report = newReport()
+		}
+
+		fmt.Fprintf(logOut, "⚡ OrchAxon AutoTest v%s (Batch Mode)\n", core.Version)
+		sum, errs := gen.run(files, *jobsFlag, out, logOut)
+
+		if err := out.Close(); err != nil {
+			errs = append(errs, runError{Spec: *fileFlag, Code: ExitWriteError, Err: fmt.Errorf("error finishing output: %v", err)})
+		}
+
+		elapsed := time.Since(start)
+		fmt.Fprintf(logOut, "\n✨ Done! %s in %.2fs\n", sum, elapsed.Seconds())
+
+		if gen.report != nil {
+			if err := writeReport(gen.report, *reportFlag, *reportFileFlag, stdout, sum); err != nil {
+				errs = append(errs, runError{Spec: *fileFlag, Code: ExitWriteError, Err: fmt.Errorf("error writing report: %v", err)})
+			}
+		}
+
+		printErrors(logOut, errs)
+		return exitCode(errs)
+	}
+
+	// --- MODO 2: SIMPLE CLI FLAGS ---
+	if *langFlag != "" && *classFlag != "" {
+		fakeConfig := core.MetaFramework{
+			Meta:      core.MetaInfo{Lang: *langFlag},
+			Target:    core.TargetInfo{ClassName: *classFlag, MethodName: "MyMethod"},
+			Scenarios: []core.Scenario{{ID: "should_work", Description: "Should return expected result"}},
+		}
+
+		defaults.applyDefaults(&fakeConfig.Meta)
+		frameworks.apply(&fakeConfig.Meta)
+
+		// Gera Código
+		code, err := core.ProcessTemplate(fakeConfig, *langFlag)
+		if err != nil {
+			fmt.Fprintf(stderr, "❌ Error: %v\n", err)
+			return generationCode(err)
+		}
+
+		// -print no modo simples mantém a saída crua, sem delimitadores
+		if *printFlag && !*tarFlag {
+			if _, err := fmt.Fprintln(stdout, code); err != nil {
+				return ExitWriteError
+			}
+			return ExitOK
+		}
+
+		finalOutput := *outFlag
+		if finalOutput == "" {
+			finalOutput = core.Filename(*classFlag, *langFlag)
+		}
+		f := GeneratedFile{Lang: *langFlag, Path: filepath.Join(outputDir, finalOutput), Code: code, Status: StatusGenerated}
+
+		if err := out.Write(f); err != nil {
+			fmt.Fprintf(stderr, "❌ Error saving: %v\n", err)
+			return ExitWriteError
+		}
+		if err := out.Close(); err != nil {
+			fmt.Fprintf(stderr, "❌ Error finishing output: %v\n", err)
+			return ExitWriteError
+		}
+
+		elapsed := time.Since(start)
+		fmt.Fprintf(logOut, "⚡ OrchAxon AutoTest v%s (Simple Mode)\n", core.Version)
+		fmt.Fprintf(logOut, "✓ Generated %s (%.2fs)\n", f.Path, elapsed.Seconds())
+		return ExitOK
+	}
+
+	// --- HELP ---
+	fmt.Fprintln(stderr, "❌ Usage:")
+	fmt.Fprintln(stderr, "  Batch Mode:  autotest -file \"specs/*.json\"")
+	fmt.Fprintln(stderr, "  Pipe Mode:   cat spec.json | autotest -file - -print")
+	fmt.Fprintln(stderr, "  Watch Mode:  autotest watch -file \"specs/*.json\" [-templates dir]")
+	fmt.Fprintln(stderr, "  Simple Mode: autotest -lang node -class User")
+	return ExitUsage
+}
+
+// expandSpecs resolve o padrão do -file em uma lista de arquivos.
+func expandSpecs(pattern string) ([]string, error) {
+	if pattern == "-" {
+		return []string{stdinName}, nil
+	}
+
+	// Expande wildcards (ex: *.json)
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("error with file pattern: %v", err)
+	}
+	if len(files) == 0 {
+		// Tenta usar o arquivo literal se o glob não achou nada (caso o usuário não use *)
+		if _, err := os.Stat(pattern); err != nil {
+			return nil, fmt.Errorf("no files found matching: %s", pattern)
+		}
+		files = []string{pattern}
+	}
+	return files, nil
+}
+
+// readSpec lê o conteúdo bruto de uma spec do disco ou do stdin.
+func readSpec(path string, stdin io.Reader) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == stdinName {
+		data, err = io.ReadAll(stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading file %s: %v", path, err)
+	}
+	return data, nil
+}
+
+// parseSpec decodifica o JSON de uma spec.
+func parseSpec(path string, data []byte) (core.MetaFramework, error) {
+	var config core.MetaFramework
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("error parsing JSON in %s: %v", path, err)
+	}
+	return config, nil
+}
+
+// generator concentra as opções compartilhadas pelos workers do batch.
+type generator struct {
+	stdin      io.Reader
+	outputDir  string
+	cache      *cache  // nil quando o cache está desligado
+	report     *report // nil quando -report não foi pedido
+	force      bool
+	frameworks *frameworkFlag // Escolhas de -framework, aplicadas sobre cada spec
+	defaults   *frameworkFlag // Frameworks do arquivo de configuração, abaixo da spec
+}
+
+// summary conta os arquivos por status ao final de uma execução.
+type summary struct {
+	Generated, Unchanged, Skipped, Errors int
+}
+
+func (s summary) String() string {
+	text := fmt.Sprintf("%d generated, %d unchanged, %d skipped", s.Generated, s.Unchanged, s.Skipped)
+	if s.Errors > 0 {
+		text += fmt.Sprintf(", %d errors", s.Errors)
+	}
+	return text
+}
+
+// run processa as specs, emite os arquivos gerados em "out" e atualiza o cache.
+// Nenhum erro interrompe o batch: todos são coletados e devolvidos no final.
+func (g *generator) run(files []string, jobs int, out sink, logOut io.Writer) (summary, []runError) {
+	var sum summary
+	var errs []runError
+
+	// Os resultados chegam em paralelo, mas são consumidos na ordem dos arquivos
+	for r := range g.processSpecs(files, jobs) {
+		if r.Err != nil {
+			fmt.Fprintf(logOut, "❌ Failed to process %s: %v\n", r.Spec, r.Err)
+			sum.Errors++
+			errs = append(errs, runError{Spec: r.Spec, Code: ExitInvalidInput, Err: r.Err})
+			g.report.add(GeneratedFile{Spec: r.Spec, Status: StatusError, Err: r.Err, Duration: r.Duration})
+			continue
+		}
+
+		for _, f := range r.Files {
+			switch f.Status {
+			case StatusSkipped:
+				fmt.Fprintf(logOut, "⚠️ Skipping %s in %s: %v\n", f.Lang, f.Spec, f.Err)
+				sum.Skipped++
+				errs = append(errs, runError{Spec: f.Spec, Lang: f.Lang, Code: f.exitCode, Err: f.Err})
+			case StatusError:
+				fmt.Fprintf(logOut, "❌ Failed to generate %s from %s: %v\n", f.Lang, f.Spec, f.Err)
+				sum.Errors++
+				errs = append(errs, runError{Spec: f.Spec, Lang: f.Lang, Code: f.exitCode, Err: f.Err})
+			case StatusUnchanged:
+				sum.Unchanged++
+			case StatusGenerated:
+				writeStart := time.Now()
+				err := out.Write(f)
+				f.Duration += time.Since(writeStart)
+				if err != nil {
+					fmt.Fprintf(logOut, "❌ Error saving %s: %v\n", f.Path, err)
+					f.Status, f.Err, f.exitCode = StatusError, err, ExitWriteError
+					sum.Errors++
+					errs = append(errs, runError{Spec: f.Spec, Lang: f.Lang, Code: f.exitCode, Err: err})
+					break
+				}
+				if g.cache != nil {
+					g.cache.Put(f.Path, f.entry)
+				}
+				fmt.Fprintf(logOut, "✓ Generated %s (from %s)\n", f.Path, filepath.Base(f.Spec))
+				sum.Generated++
+			}
+			g.report.add(f)
+		}
+	}
+
+	if g.cache != nil {
+		if err := g.cache.Save(); err != nil {
+			fmt.Fprintf(logOut, "⚠️ Warning: could not save cache %s: %v\n", g.cache.path, err)
+		}
+	}
+	return sum, errs
+}
+
+// specResult agrupa o que foi gerado a partir de uma spec.
+type specResult struct {
+	Spec     string
+	Files    []GeneratedFile
+	Err      error
+	Duration time.Duration // Tempo até a falha, quando Err != nil
+}
+
+// processSpecs carrega e gera as specs com um pool de "jobs" workers.
+// O canal devolvido entrega os resultados na mesma ordem de "files".
+func (g *generator) processSpecs(files []string, jobs int) <-chan specResult {
+	slots := make([]chan specResult, len(files))
+	for i := range slots {
+		slots[i] = make(chan specResult, 1)
+	}
+
+	indexes := make(chan int)
+	go func() {
+		for i := range files {
+			indexes <- i
+		}
+		close(indexes)
+	}()
+
+	for w := 0; w < jobs; w++ {
+		go func() {
+			for i := range indexes {
+				slots[i] <- g.processSpec(files[i])
+			}
+		}()
+	}
+
+	ordered := make(chan specResult)
+	go func() {
+		for _, slot := range slots {
+			ordered <- <-slot
+		}
+		close(ordered)
+	}()
+	return ordered
+}
+
+// processSpec lê uma spec e gera o código de todas as linguagens pedidas.
+// Linguagens cujo arquivo está em dia com o cache não são regeneradas.
+func (g *generator) processSpec(specName string) specResult {
+	start := time.Now()
+	result := specResult{Spec: specName}
+	fail := func(err error) specResult {
+		result.Err = err
+		result.Duration = time.Since(start)
+		return result
+	}
+
+	data, err := readSpec(specName, g.stdin)
+	if err != nil {
+		return fail(err)
+	}
+	config, err := parseSpec(specName, data)
+	if err != nil {
+		return fail(err)
+	}
+	g.defaults.applyDefaults(&config.Meta)
+	g.frameworks.apply(&config.Meta)
+
+	languages := config.Languages()
+	if len(languages) == 0 {
+		return fail(fmt.Errorf("no language specified in %s", specName))
+	}
+
+	for _, lang := range languages {
+		langStart := time.Now()
+		f := GeneratedFile{
+			Spec:  specName,
+			Lang:  lang,
+			Path:  filepath.Join(g.outputDir, core.Filename(config.Target.ClassName, lang)),
+			entry: newCacheEntry(data, lang, config.Meta),
+		}
+
+		if g.cache != nil && !g.force && g.cache.Fresh(f.Path, f.entry) {
+			f.Status = StatusUnchanged
+			f.Duration = time.Since(langStart)
+			result.Files = append(result.Files, f)
+			continue
+		}
+
+		code, err := core.ProcessTemplate(config, lang)
+		if err != nil {
+			// Linguagem desconhecida só pula o arquivo; erro de template é falha de geração
+			f.Status = StatusError
+			if errors.Is(err, core.ErrUnsupportedLanguage) {
+				f.Status = StatusSkipped
+			}
+			f.Err = err
+			f.exitCode = generationCode(err)
+		} else {
+			f.Status = StatusGenerated
+			f.Code = code
+		}
+		f.Duration = time.Since(langStart)
+		result.Files = append(result.Files, f)
+	}
+	return result
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeSpecs grava "n" specs em um diretório temporário; a cada "badEvery"
+// specs, uma tem JSON inválido. Devolve os caminhos na ordem de criação.
+func writeSpecs(t *testing.T, n, badEvery int) []string {
+	t.Helper()
+	dir := t.TempDir()
+	files := make([]string, n)
+	for i := range files {
+		files[i] = filepath.Join(dir, fmt.Sprintf("spec%02d.json", i))
+		data := fmt.Sprintf(`{"meta": {"langs": ["go", "python", "ts"]}, "target": {"class_name": "Svc%d", "method_name": "run"}}`, i)
+		if badEvery > 0 && i%badEvery == 0 {
+			data = "{"
+		}
+		if err := os.WriteFile(files[i], []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return files
+}
+
+func TestProcessSpecsKeepsFileOrder(t *testing.T) {
+	tests := []struct {
+		name     string
+		specs    int
+		jobs     int
+		badEvery int
+	}{
+		{"single worker", 5, 1, 0},
+		{"more specs than workers", 24, 4, 0},
+		{"more workers than specs", 3, 16, 0},
+		{"with failing specs", 20, 8, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := writeSpecs(t, tt.specs, tt.badEvery)
+			g := &generator{outputDir: "test"}
+
+			i := 0
+			for r := range g.processSpecs(files, tt.jobs) {
+				if i >= len(files) {
+					t.Fatalf("got more than %d results", len(files))
+				}
+				if r.Spec != files[i] {
+					t.Fatalf("result %d: got %s, want %s", i, r.Spec, files[i])
+				}
+				wantErr := tt.badEvery > 0 && i%tt.badEvery == 0
+				if (r.Err != nil) != wantErr {
+					t.Errorf("%s: err = %v, want error %v", r.Spec, r.Err, wantErr)
+				}
+				if !wantErr && len(r.Files) != 3 {
+					t.Errorf("%s: got %d files, want 3", r.Spec, len(r.Files))
+				}
+				i++
+			}
+			if i != len(files) {
+				t.Errorf("got %d results, want %d", i, len(files))
+			}
+		})
+	}
+}
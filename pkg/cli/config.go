@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// Arquivo de configuração do projeto, lido do diretório atual quando existe
+const defaultConfigFile = ".autotest.json"
+
+// projectConfig guarda os padrões do projeto. Valem para as linguagens em que
+// a spec não escolhe o framework; o -framework tem prioridade sobre os dois.
+type projectConfig struct {
+	Framework  string            `json:"framework"`
+	Frameworks map[string]string `json:"frameworks"`
+}
+
+// loadConfig lê o arquivo de configuração e devolve seus frameworks já validados.
+// O arquivo padrão é opcional; um -config explícito precisa existir.
+func loadConfig(path string, explicit bool) (*frameworkFlag, error) {
+	defaults := &frameworkFlag{}
+	if path == "" {
+		return defaults, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) && !explicit {
+			return defaults, nil
+		}
+		return nil, fmt.Errorf("error reading config %s: %v", path, err)
+	}
+
+	var cfg projectConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("error parsing config %s: %v", path, err)
+	}
+	if cfg.Framework != "" {
+		if err := defaults.Set(cfg.Framework); err != nil {
+			return nil, fmt.Errorf("config %s: %v", path, err)
+		}
+	}
+	for lang, name := range cfg.Frameworks {
+		if err := defaults.Set(lang + "=" + name); err != nil {
+			return nil, fmt.Errorf("config %s: %v", path, err)
+		}
+	}
+	return defaults, nil
+}
+
+// isSet indica se a flag foi passada na linha de comando.
+func isSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+// Exit codes da CLI. Quando há erros de tipos diferentes,
+// vence o de maior valor (escrita > geração > entrada inválida).
+const (
+	ExitOK              = 0
+	ExitUsage           = 2 // Flags ou subcomando inválidos
+	ExitInvalidInput    = 3 // Spec ilegível, JSON inválido, linguagem ou framework não suportados
+	ExitGenerationError = 4 // Falha ao executar um template
+	ExitWriteError      = 5 // Falha ao gravar arquivos, report ou stdout
+)
+
+// runError é um erro coletado durante o batch, com o exit code correspondente.
+type runError struct {
+	Spec string
+	Lang string
+	Code int
+	Err  error
+}
+
+func (e runError) String() string {
+	if e.Lang == "" {
+		return fmt.Sprintf("%s: %v", e.Spec, e.Err)
+	}
+	return fmt.Sprintf("%s [%s]: %v", e.Spec, e.Lang, e.Err)
+}
+
+// generationCode classifica um erro devolvido por core.ProcessTemplate.
+func generationCode(err error) int {
+	if errors.Is(err, core.ErrUnsupportedLanguage) || errors.Is(err, core.ErrUnsupportedFramework) ||
+		errors.Is(err, core.ErrInvalidNaming) || errors.Is(err, core.ErrInvalidType) ||
+		errors.Is(err, core.ErrInvalidValue) || errors.Is(err, core.ErrInvalidTarget) {
+		return ExitInvalidInput
+	}
+	return ExitGenerationError
+}
+
+// exitCode escolhe o exit code final a partir dos erros coletados.
+func exitCode(errs []runError) int {
+	code := ExitOK
+	for _, e := range errs {
+		code = max(code, e.Code)
+	}
+	return code
+}
+
+// printErrors imprime o resumo final de erros do batch.
+func printErrors(w io.Writer, errs []runError) {
+	if len(errs) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n❌ %d error(s):\n", len(errs))
+	for _, e := range errs {
+		fmt.Fprintf(w, "  - %s\n", e)
+	}
+}
@@ -0,0 +1,59 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+func TestGenerationCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"unsupported language", core.ErrUnsupportedLanguage, ExitInvalidInput},
+		{"unsupported framework", core.ErrUnsupportedFramework, ExitInvalidInput},
+		{"invalid naming", core.ErrInvalidNaming, ExitInvalidInput},
+		{"invalid type", core.ErrInvalidType, ExitInvalidInput},
+		{"invalid value", core.ErrInvalidValue, ExitInvalidInput},
+		{"invalid target", core.ErrInvalidTarget, ExitInvalidInput},
+		{"wrapped sentinel", fmt.Errorf("inputs[0]: %w", core.ErrInvalidValue), ExitInvalidInput},
+		{"template failure", errors.New("template: exec error"), ExitGenerationError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := generationCode(tt.err); got != tt.want {
+				t.Errorf("generationCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		codes []int
+		want  int
+	}{
+		{"no errors", nil, ExitOK},
+		{"single error", []int{ExitGenerationError}, ExitGenerationError},
+		{"write wins", []int{ExitInvalidInput, ExitWriteError, ExitGenerationError}, ExitWriteError},
+		{"generation over input", []int{ExitInvalidInput, ExitGenerationError, ExitInvalidInput}, ExitGenerationError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errs []runError
+			for _, code := range tt.codes {
+				errs = append(errs, runError{Spec: "a.json", Code: code, Err: errors.New("boom")})
+			}
+			if got := exitCode(errs); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.codes, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+// frameworkFlag implementa -framework: "nunit+moq" vale para as linguagens
+// que conhecem esse framework e "csharp=nunit,ts=vitest" escolhe por linguagem.
+// Pode ser repetida; os valores têm prioridade sobre o "meta" da spec.
+// Também guarda os padrões do arquivo de configuração (ver loadConfig).
+type frameworkFlag struct {
+	all    string
+	byLang map[string]string // Chave: linguagem canônica
+}
+
+func (f *frameworkFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	parts := []string{}
+	if f.all != "" {
+		parts = append(parts, f.all)
+	}
+	for lang, name := range f.byLang {
+		parts = append(parts, lang+"="+name)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *frameworkFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		lang, name, found := strings.Cut(item, "=")
+		if !found {
+			if err := core.CheckFramework(item); err != nil {
+				return err
+			}
+			f.all = item
+			continue
+		}
+
+		canonical, ok := core.CanonicalLang(strings.TrimSpace(lang))
+		if !ok {
+			return fmt.Errorf("unknown language %q", lang)
+		}
+		if _, err := core.ResolveStack(canonical, name); err != nil {
+			return err
+		}
+		if f.byLang == nil {
+			f.byLang = map[string]string{}
+		}
+		f.byLang[canonical] = strings.TrimSpace(name)
+	}
+	return nil
+}
+
+// choice devolve o framework escolhido para a linguagem canônica, se houver.
+func (f *frameworkFlag) choice(lang string) (string, bool) {
+	if name, ok := f.byLang[lang]; ok {
+		return name, true
+	}
+	return f.all, core.KnowsFramework(lang, f.all)
+}
+
+// apply sobrescreve a escolha de framework da spec com a da linha de comando,
+// só nas linguagens em que a linha de comando escolheu algo.
+func (f *frameworkFlag) apply(meta *core.MetaInfo) {
+	f.merge(meta, true)
+}
+
+// applyDefaults completa a spec com os padrões do arquivo de configuração,
+// só nas linguagens em que a spec não escolheu nada.
+func (f *frameworkFlag) applyDefaults(meta *core.MetaInfo) {
+	f.merge(meta, false)
+}
+
+// merge grava em "frameworks" as escolhas de "f" por linguagem, que vencem o
+// "framework" global e o "style" da spec; "override" dá prioridade a "f" sobre a spec.
+func (f *frameworkFlag) merge(meta *core.MetaInfo, override bool) {
+	if f == nil || (f.all == "" && len(f.byLang) == 0) {
+		return
+	}
+
+	frameworks := map[string]string{}
+	for lang, name := range meta.Frameworks {
+		if canonical, ok := core.CanonicalLang(lang); ok {
+			frameworks[canonical] = name
+		}
+	}
+	for _, lang := range core.FrameworkLangs() {
+		if name, chosen := f.choice(lang); chosen && (override || !meta.ChoosesFramework(lang)) {
+			frameworks[lang] = name
+		}
+	}
+	meta.Frameworks = frameworks
+}
@@ -0,0 +1,228 @@
+package cli
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+// Formatos aceitos em -report
+const (
+	reportJSON  = "json"
+	reportJUnit = "junit"
+)
+
+// report acumula o resultado de cada (spec, linguagem) para consumo por CI.
+// Todos os métodos aceitam receiver nil, o que equivale a report desligado.
+type report struct {
+	started time.Time
+	entries []reportEntry
+}
+
+type reportEntry struct {
+	Spec       string  `json:"spec"`
+	Lang       string  `json:"lang,omitempty"`
+	Output     string  `json:"output,omitempty"`
+	Status     Status  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+func newReport() *report {
+	return &report{started: time.Now()}
+}
+
+func (r *report) add(f GeneratedFile) {
+	if r == nil {
+		return
+	}
+
+	entry := reportEntry{
+		Spec:       f.Spec,
+		Lang:       f.Lang,
+		Status:     f.Status,
+		DurationMs: milliseconds(f.Duration),
+	}
+	// Linguagens puladas não chegam a ter arquivo de saída
+	if f.Status != StatusSkipped {
+		entry.Output = filepath.ToSlash(f.Path)
+	}
+	if f.Err != nil {
+		entry.Error = f.Err.Error()
+	}
+	r.entries = append(r.entries, entry)
+}
+
+// Write serializa o report no formato pedido.
+func (r *report) Write(w io.Writer, format string, sum summary) error {
+	switch format {
+	case reportJSON:
+		return r.writeJSON(w, sum)
+	case reportJUnit:
+		return r.writeJUnit(w)
+	default:
+		return fmt.Errorf("unknown report format: %s (use %s or %s)", format, reportJSON, reportJUnit)
+	}
+}
+
+// writeReport grava o report em "path" ou, se vazio, no stdout.
+func writeReport(r *report, format, path string, stdout io.Writer, sum summary) error {
+	if path == "" {
+		return r.Write(stdout, format, sum)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(f, format, sum); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// --- JSON ---
+
+type jsonReport struct {
+	Generator  string        `json:"generator"`
+	StartedAt  time.Time     `json:"started_at"`
+	DurationMs float64       `json:"duration_ms"`
+	Summary    jsonSummary   `json:"summary"`
+	Results    []reportEntry `json:"results"`
+}
+
+type jsonSummary struct {
+	Generated int `json:"generated"`
+	Unchanged int `json:"unchanged"`
+	Skipped   int `json:"skipped"`
+	Errors    int `json:"errors"`
+}
+
+func (r *report) writeJSON(w io.Writer, sum summary) error {
+	results := r.entries
+	if results == nil {
+		results = []reportEntry{}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(jsonReport{
+		Generator:  core.Version,
+		StartedAt:  r.started.UTC(),
+		DurationMs: milliseconds(time.Since(r.started)),
+		Summary: jsonSummary{
+			Generated: sum.Generated,
+			Unchanged: sum.Unchanged,
+			Skipped:   sum.Skipped,
+			Errors:    sum.Errors,
+		},
+		Results: results,
+	})
+}
+
+// --- JUNIT XML ---
+
+// Cada spec vira uma <testsuite> e cada linguagem um <testcase>.
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Name    string       `xml:"name,attr"`
+	Tests   int          `xml:"tests,attr"`
+	Fails   int          `xml:"failures,attr"`
+	Skipped int          `xml:"skipped,attr"`
+	Time    string       `xml:"time,attr"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name    string      `xml:"name,attr"`
+	Tests   int         `xml:"tests,attr"`
+	Fails   int         `xml:"failures,attr"`
+	Skipped int         `xml:"skipped,attr"`
+	Time    string      `xml:"time,attr"`
+	Cases   []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+}
+
+func (r *report) writeJUnit(w io.Writer) error {
+	root := junitSuites{Name: "orchaxon-autotest", Time: seconds(time.Since(r.started))}
+	index := map[string]int{}
+	var durations []time.Duration
+
+	for _, e := range r.entries {
+		i, ok := index[e.Spec]
+		if !ok {
+			i = len(root.Suites)
+			index[e.Spec] = i
+			root.Suites = append(root.Suites, junitSuite{Name: e.Spec})
+			durations = append(durations, 0)
+		}
+		suite := &root.Suites[i]
+
+		name := e.Lang
+		if name == "" {
+			name = "spec"
+		}
+		d := time.Duration(e.DurationMs * float64(time.Millisecond))
+		durations[i] += d
+		tc := junitCase{Name: name, ClassName: e.Spec, Time: seconds(d)}
+
+		switch e.Status {
+		case StatusError:
+			tc.Failure = &junitMessage{Message: e.Error}
+			suite.Fails++
+			root.Fails++
+		case StatusSkipped:
+			tc.Skipped = &junitMessage{Message: e.Error}
+			suite.Skipped++
+			root.Skipped++
+		default:
+			tc.SystemOut = fmt.Sprintf("%s %s", e.Status, e.Output)
+		}
+
+		suite.Tests++
+		root.Tests++
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	for i, d := range durations {
+		root.Suites[i].Time = seconds(d)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+// sampleReport cobre os quatro status, com duas specs para agrupar no JUnit.
+func sampleReport() *report {
+	r := newReport()
+	r.add(GeneratedFile{Spec: "a.json", Lang: "go", Path: filepath.Join("out", "a_test.go"), Status: StatusGenerated, Duration: 1500 * time.Microsecond})
+	r.add(GeneratedFile{Spec: "a.json", Lang: "python", Path: filepath.Join("out", "test_a.py"), Status: StatusUnchanged})
+	r.add(GeneratedFile{Spec: "a.json", Lang: "rust", Path: "ignored", Status: StatusSkipped, Err: errors.New("no methods")})
+	r.add(GeneratedFile{Spec: "b.json", Status: StatusError, Err: errors.New("invalid JSON")})
+	return r
+}
+
+func TestReportNilReceiver(t *testing.T) {
+	var r *report
+	r.add(GeneratedFile{Spec: "a.json", Status: StatusGenerated})
+}
+
+func TestReportJSON(t *testing.T) {
+	var buf bytes.Buffer
+	sum := summary{Generated: 1, Unchanged: 1, Skipped: 1, Errors: 1}
+	if err := sampleReport().Write(&buf, reportJSON, sum); err != nil {
+		t.Fatal(err)
+	}
+
+	var got jsonReport
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if got.Generator != core.Version {
+		t.Errorf("generator = %q, want %q", got.Generator, core.Version)
+	}
+	if want := (jsonSummary{Generated: 1, Unchanged: 1, Skipped: 1, Errors: 1}); got.Summary != want {
+		t.Errorf("summary = %+v, want %+v", got.Summary, want)
+	}
+
+	want := []reportEntry{
+		{Spec: "a.json", Lang: "go", Output: "out/a_test.go", Status: StatusGenerated, DurationMs: 1.5},
+		{Spec: "a.json", Lang: "python", Output: "out/test_a.py", Status: StatusUnchanged},
+		{Spec: "a.json", Lang: "rust", Status: StatusSkipped, Error: "no methods"},
+		{Spec: "b.json", Status: StatusError, Error: "invalid JSON"},
+	}
+	if !reflect.DeepEqual(got.Results, want) {
+		t.Errorf("results = %+v\nwant %+v", got.Results, want)
+	}
+}
+
+func TestReportJSONEmptyResults(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newReport().Write(&buf, reportJSON, summary{}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"results": []`)) {
+		t.Errorf("empty report should have an empty results array:\n%s", buf.String())
+	}
+}
+
+func TestReportJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Write(&buf, reportJUnit, summary{}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte(xml.Header)) {
+		t.Errorf("missing XML header:\n%s", buf.String())
+	}
+
+	var got junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if got.Tests != 4 || got.Fails != 1 || got.Skipped != 1 {
+		t.Errorf("testsuites tests/failures/skipped = %d/%d/%d, want 4/1/1", got.Tests, got.Fails, got.Skipped)
+	}
+
+	tests := []struct {
+		suite                 string
+		cases, fails, skipped int
+		names                 []string
+	}{
+		{"a.json", 3, 0, 1, []string{"go", "python", "rust"}},
+		{"b.json", 1, 1, 0, []string{"spec"}},
+	}
+	if len(got.Suites) != len(tests) {
+		t.Fatalf("got %d suites, want %d", len(got.Suites), len(tests))
+	}
+	for i, tt := range tests {
+		s := got.Suites[i]
+		if s.Name != tt.suite || s.Tests != tt.cases || s.Fails != tt.fails || s.Skipped != tt.skipped {
+			t.Errorf("suite %d = %s %d/%d/%d, want %s %d/%d/%d", i, s.Name, s.Tests, s.Fails, s.Skipped, tt.suite, tt.cases, tt.fails, tt.skipped)
+		}
+		var names []string
+		for _, c := range s.Cases {
+			names = append(names, c.Name)
+			if c.ClassName != tt.suite {
+				t.Errorf("%s/%s: classname = %q", tt.suite, c.Name, c.ClassName)
+			}
+		}
+		if !reflect.DeepEqual(names, tt.names) {
+			t.Errorf("suite %s cases = %v, want %v", tt.suite, names, tt.names)
+		}
+	}
+
+	if c := got.Suites[1].Cases[0]; c.Failure == nil || c.Failure.Message != "invalid JSON" {
+		t.Errorf("failure = %+v, want message %q", c.Failure, "invalid JSON")
+	}
+	if c := got.Suites[0].Cases[2]; c.Skipped == nil || c.Skipped.Message != "no methods" {
+		t.Errorf("skipped = %+v, want message %q", c.Skipped, "no methods")
+	}
+	if c := got.Suites[0].Cases[0]; c.SystemOut != "generated out/a_test.go" {
+		t.Errorf("system-out = %q", c.SystemOut)
+	}
+}
+
+func TestReportUnknownFormat(t *testing.T) {
+	if err := newReport().Write(&bytes.Buffer{}, "yaml", summary{}); err == nil {
+		t.Error("expected an error for an unknown format")
+	}
+}
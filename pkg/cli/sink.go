@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// sink é o destino dos arquivos gerados: disco, stdout delimitado ou tar.
+type sink interface {
+	Write(f GeneratedFile) error
+	Close() error
+}
+
+// --- DISCO ---
+
+type diskSink struct{}
+
+func (diskSink) Write(f GeneratedFile) error {
+	if err := os.MkdirAll(filepath.Dir(f.Path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.Path, []byte(f.Code), 0644)
+}
+
+func (diskSink) Close() error { return nil }
+
+// --- STDOUT EM SEÇÕES ---
+
+// sectionSink escreve cada arquivo precedido de um cabeçalho "==> caminho <==",
+// no mesmo formato do head/tail com múltiplos arquivos.
+type sectionSink struct {
+	w     io.Writer
+	count int
+}
+
+func newSectionSink(w io.Writer) *sectionSink {
+	return &sectionSink{w: w}
+}
+
+func (s *sectionSink) Write(f GeneratedFile) error {
+	if s.count > 0 {
+		if _, err := fmt.Fprintln(s.w); err != nil {
+			return err
+		}
+	}
+	s.count++
+
+	if _, err := fmt.Fprintf(s.w, "==> %s <==\n", filepath.ToSlash(f.Path)); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(s.w, f.Code)
+	return err
+}
+
+func (s *sectionSink) Close() error { return nil }
+
+// --- STDOUT EM TAR ---
+
+type tarSink struct {
+	tw  *tar.Writer
+	now time.Time
+}
+
+func newTarSink(w io.Writer) *tarSink {
+	return &tarSink{tw: tar.NewWriter(w), now: time.Now()}
+}
+
+func (s *tarSink) Write(f GeneratedFile) error {
+	hdr := &tar.Header{
+		Name:    filepath.ToSlash(f.Path),
+		Mode:    0644,
+		Size:    int64(len(f.Code)),
+		ModTime: s.now,
+	}
+	if err := s.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.WriteString(s.tw, f.Code)
+	return err
+}
+
+func (s *tarSink) Close() error { return s.tw.Close() }
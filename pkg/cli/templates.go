@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+// Extensão dos templates customizados: <lang>.tmpl (ex: go.tmpl, csharp.tmpl)
+const templateExt = ".tmpl"
+
+// loadTemplateDir substitui os templates embutidos pelos arquivos
+// "<lang>.tmpl" encontrados em dir. Linguagens sem arquivo usam o original.
+func loadTemplateDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error reading templates from %s: %v", dir, err)
+	}
+
+	sources := map[string]string{}
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != templateExt {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return fmt.Errorf("error reading template %s: %v", e.Name(), err)
+		}
+		sources[strings.TrimSuffix(e.Name(), templateExt)] = string(data)
+	}
+
+	return core.SetTemplateOverrides(sources)
+}
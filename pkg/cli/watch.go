@@ -0,0 +1,173 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/Mr-Fullstack/orchaxon-autotest/pkg/core"
+)
+
+// runWatch implementa "autotest watch": observa as specs e o diretório de
+// templates por polling e regenera o que mudou. Erros são apenas reportados;
+// o processo só termina com Ctrl+C.
+func runWatch(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("autotest watch", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	fileFlag := flags.String("file", "", "Spec files to watch (supports wildcards like specs/*.json)")
+	templatesFlag := flags.String("templates", "", "Directory with custom <lang>.tmpl templates to watch")
+	intervalFlag := flags.Duration("interval", 500*time.Millisecond, "Polling interval")
+	jobsFlag := flags.Int("j", runtime.NumCPU(), "Number of specs processed in parallel")
+	cacheFlag := flags.String("cache", defaultCacheFile, "Incremental cache file (empty disables the cache)")
+	var frameworks frameworkFlag
+	flags.Var(&frameworks, "framework", "Test framework, for all languages (nunit+moq) or per language (csharp=nunit,ts=vitest)")
+	configFlag := flags.String("config", defaultConfigFile, "Project config file with default frameworks (empty disables it)")
+
+	if err := flags.Parse(args); err != nil {
+		return ExitUsage
+	}
+	if *fileFlag == "" {
+		fmt.Fprintln(stderr, "❌ Usage: autotest watch -file \"specs/*.json\" [-templates dir]")
+		return ExitUsage
+	}
+	if *intervalFlag <= 0 {
+		fmt.Fprintf(stderr, "❌ -interval must be positive, got %v\n", *intervalFlag)
+		return ExitUsage
+	}
+	if *jobsFlag < 1 {
+		*jobsFlag = 1
+	}
+
+	defaults, err := loadConfig(*configFlag, isSet(flags, "config"))
+	if err != nil {
+		fmt.Fprintf(stderr, "❌ %v\n", err)
+		return ExitInvalidInput
+	}
+
+	const outputDir = "test"
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		fmt.Fprintf(stderr, "❌ Error creating directory: %v\n", err)
+		return ExitWriteError
+	}
+
+	gen := &generator{outputDir: outputDir, frameworks: &frameworks, defaults: defaults}
+	if *cacheFlag != "" {
+		gen.cache = loadCache(*cacheFlag)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Fprintf(stdout, "👀 OrchAxon AutoTest v%s (Watch Mode) - watching %s", core.Version, *fileFlag)
+	if *templatesFlag != "" {
+		fmt.Fprintf(stdout, " and %s", *templatesFlag)
+	}
+	fmt.Fprintln(stdout, " (Ctrl+C to stop)")
+
+	var specs, tmpls fingerprint
+	first := true
+
+	ticker := time.NewTicker(*intervalFlag)
+	defer ticker.Stop()
+
+	for {
+		// 1. Templates: recarrega e força a revisão de todas as specs
+		templatesChanged := false
+		if *templatesFlag != "" {
+			current := scan(filepath.Join(*templatesFlag, "*"+templateExt))
+			if first || !current.equal(tmpls) {
+				tmpls = current
+				if err := loadTemplateDir(*templatesFlag); err != nil {
+					fmt.Fprintf(stdout, "❌ %v (keeping previous templates)\n", err)
+				} else {
+					templatesChanged = true
+					if !first {
+						fmt.Fprintf(stdout, "↻ Templates reloaded from %s\n", *templatesFlag)
+					}
+				}
+			}
+		}
+
+		// 2. Specs: só as novas ou modificadas são reprocessadas
+		current := scan(*fileFlag)
+		var changed []string
+		for _, path := range current.paths() {
+			if first || templatesChanged || current[path] != specs[path] {
+				changed = append(changed, path)
+			}
+		}
+		for _, path := range specs.paths() {
+			if _, ok := current[path]; !ok {
+				fmt.Fprintf(stdout, "• Removed %s\n", path)
+			}
+		}
+		specs = current
+
+		if len(changed) > 0 {
+			start := time.Now()
+			// Os erros já aparecem inline; no watch eles nunca encerram o processo
+			sum, _ := gen.run(changed, *jobsFlag, diskSink{}, stdout)
+			fmt.Fprintf(stdout, "✨ %d spec(s) checked: %s in %.2fs\n", len(changed), sum, time.Since(start).Seconds())
+		}
+		first = false
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(stdout, "\n👋 Watch stopped")
+			return ExitOK
+		case <-ticker.C:
+		}
+	}
+}
+
+// fingerprint guarda o estado (mtime + tamanho) de cada arquivo observado.
+type fingerprint map[string]fileState
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// scan expande o padrão e coleta o estado atual dos arquivos encontrados.
+func scan(pattern string) fingerprint {
+	fp := fingerprint{}
+	matches, _ := filepath.Glob(pattern)
+	for _, path := range matches {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		fp[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+	}
+	return fp
+}
+
+func (fp fingerprint) equal(other fingerprint) bool {
+	if len(fp) != len(other) {
+		return false
+	}
+	for path, state := range fp {
+		if other[path] != state {
+			return false
+		}
+	}
+	return true
+}
+
+// paths devolve os arquivos em ordem, para manter a saída determinística.
+func (fp fingerprint) paths() []string {
+	paths := make([]string, 0, len(fp))
+	for path := range fp {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
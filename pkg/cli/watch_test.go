@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchRejectsInterval(t *testing.T) {
+	for _, interval := range []string{"0", "-1s"} {
+		t.Run(interval, func(t *testing.T) {
+			code := runWatch([]string{"-file", "specs/*.json", "-interval", interval}, io.Discard, io.Discard)
+			if code != ExitUsage {
+				t.Errorf("runWatch(-interval %s) = %d, want %d", interval, code, ExitUsage)
+			}
+		})
+	}
+}
+
+func TestScanDetectsChanges(t *testing.T) {
+	dir := t.TempDir()
+	pattern := filepath.Join(dir, "*.json")
+	a, b := filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(a, "{}")
+	write(b, "{}")
+	if err := os.Mkdir(filepath.Join(dir, "dir.json"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	before := scan(pattern)
+	if got := before.paths(); !reflect.DeepEqual(got, []string{a, b}) {
+		t.Fatalf("paths() = %v, want %v (directories are skipped)", got, []string{a, b})
+	}
+	if !scan(pattern).equal(before) {
+		t.Error("rescanning unchanged files should be equal")
+	}
+
+	write(a, `{"id": 1}`)
+	after := scan(pattern)
+	if after.equal(before) || after[a] == before[a] || after[b] != before[b] {
+		t.Error("size change not detected only in a.json")
+	}
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(b, later, later); err != nil {
+		t.Fatal(err)
+	}
+	touched := scan(pattern)
+	if touched[b] == after[b] || touched[a] != after[a] {
+		t.Error("mtime change not detected only in b.json")
+	}
+
+	if err := os.Remove(a); err != nil {
+		t.Fatal(err)
+	}
+	removed := scan(pattern)
+	if _, ok := removed[a]; ok || removed.equal(touched) {
+		t.Error("removed file still in the fingerprint")
+	}
+}
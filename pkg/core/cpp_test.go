@@ -0,0 +1,57 @@
+package core
+
+import "testing"
+
+func TestCppMockErrors(t *testing.T) {
+	code := generate(t, mockErrorSpec, "cpp", "")
+	assertContains(t, code,
+		"#include <stdexcept>",
+		"using ::testing::Throw;",
+		`EXPECT_CALL(repo, find(_)).WillOnce(Return(User{.id = 1}));`,
+		`EXPECT_CALL(repo, find(_)).WillOnce(Throw(std::runtime_error("db down")));`,
+		`EXPECT_CALL(repo, save(_)).WillOnce(Throw(std::runtime_error("disk full")));`,
+		`EXPECT_CALL(repo, ping()).WillOnce(Throw(std::runtime_error("timeout")));`,
+	)
+	assertNotContains(t, code, "Return(nullptr)")
+
+	ok := generate(t, `{"target": {"class_name": "Svc", "method_name": "run"}, "dependencies": [{"field_name": "repo", "interface_name": "Repo"}],
+	  "scenarios": [{"id": "ok", "mocks_setup": [{"dependency": "repo", "method": "count", "return_value": 1}]}]}`, "cpp", "")
+	assertNotContains(t, ok, "<stdexcept>", "Throw")
+}
+
+func TestCppOptionalNull(t *testing.T) {
+	code := generate(t, `{"target": {"class_name": "Svc", "method_name": "run"},
+	  "dependencies": [{"field_name": "repo", "interface_name": "Repo", "methods": [{"name": "find", "returns": "User?"}]}],
+	  "scenarios": [{"id": "missing", "mocks_setup": [{"dependency": "repo", "method": "find", "return_value": null}]}]}`, "cpp", "")
+	assertContains(t, code, "EXPECT_CALL(repo, find()).WillOnce(Return(std::nullopt));")
+}
+
+func TestCppIncludes(t *testing.T) {
+	tests := []struct {
+		name, types string
+		want, not   []string
+	}{
+		{"primitives", `"params": [{"name": "id", "type": "int"}], "returns": "string"`, nil,
+			[]string{"<any>", "<map>", "<optional>", "<vector>"}},
+		{"optional", `"returns": "User?"`, []string{"#include <optional>"}, []string{"<vector>"}},
+		{"nested", `"params": [{"name": "ids", "type": "map<string, list<int?>>"}]`,
+			[]string{"#include <map>", "#include <optional>", "#include <vector>"}, []string{"<any>"}},
+		{"any", `"returns": "User[]", "params": [{"name": "meta", "type": "any"}]`,
+			[]string{"#include <any>", "#include <vector>"}, []string{"<map>"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code := generate(t, `{"target": {"class_name": "Svc", "method_name": "run"},
+			  "dependencies": [{"field_name": "repo", "interface_name": "Repo", "methods": [{"name": "find", `+tt.types+`}]}],
+			  "scenarios": [{"id": "ok"}]}`, "cpp", "")
+			assertContains(t, code, tt.want...)
+			assertNotContains(t, code, tt.not...)
+		})
+	}
+
+	fields := generate(t, `{"target": {"class_name": "Svc", "method_name": "run"},
+	  "types": [{"name": "User", "fields": [{"name": "tags", "type": "list<string>"}]}],
+	  "scenarios": [{"id": "ok"}]}`, "cpp", "")
+	assertContains(t, fields, "#include <vector>")
+}
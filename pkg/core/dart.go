@@ -0,0 +1,175 @@
+package core
+
+import "strings"
+
+// TEMPLATE DART (package:test + mocktail)
+const dartTmpl = `import 'package:mocktail/mocktail.dart';
+import 'package:test/test.dart';
+// import 'package:your_package/{{.Target.ClassName | ToSnake}}.dart';
+
+{{- range .Dependencies}}
+
+class Mock{{.InterfaceName}} extends Mock implements {{.InterfaceName}} {}
+{{- end}}
+{{- range $t := .ParamTypes}}
+{{- with DartFake $t}}
+
+class {{.}} extends Fake implements {{Type $t}} {}
+{{- end}}
+{{- end}}
+
+void main() {
+  group({{.Target.ClassName | Quote}}, () {
+    {{- range .Dependencies}}
+    late Mock{{.InterfaceName}} {{.FieldName | Ident}};
+    {{- end}}
+    {{- if .NeedsSut}}
+    // late {{.Target.ClassName}} sut;
+    {{- end}}
+    {{- with .ParamTypes}}
+
+    // any() needs a fallback value for every non-primitive parameter type
+    setUpAll(() {
+      {{- range .}}
+      registerFallbackValue({{DartFallback .}});
+      {{- end}}
+    });
+    {{- end}}
+
+    setUp(() {
+      {{- range .Dependencies}}
+      {{.FieldName | Ident}} = Mock{{.InterfaceName}}();
+      {{- end}}
+      {{- if .NeedsSut}}
+      {{- if eq .Target.Injection "container"}}
+      // GetIt.I.allowReassignment = true;
+      {{- range .Dependencies}}
+      // GetIt.I.registerSingleton<{{.InterfaceName}}>({{.FieldName | Ident}});
+      {{- end}}
+      // GetIt.I.registerFactory(() => {{.Target.ClassName}}({{range $i, $e := .Dependencies}}{{if $i}}, {{end}}GetIt.I(){{end}}));
+      // sut = GetIt.I<{{.Target.ClassName}}>();
+      {{- else}}
+      // sut = {{if eq .Target.Injection "factory"}}{{or .Target.Factory (printf "%s.create" .Target.ClassName)}}{{else}}{{.Target.ClassName}}{{end}}({{include "args" .}}){{range .InjectedBy "property"}}..{{.FieldName | Ident}} = {{.FieldName | Ident}}{{end}};
+      {{- end}}
+      {{- end}}
+    });
+
+    {{- range $g := .Groups}}
+    {{- if .Target.Methods}}
+
+    group({{.Target.MethodName | Quote}}, () { {{- include "tests" . | Indent 2}}
+    });
+    {{- else}}
+    {{- template "tests" .}}
+    {{- end}}
+    {{- end}}
+  });
+}
+{{- range $t := .Builders}}
+
+{{$t.Name}} build{{$t.Name}}({
+  {{- range $t.Fields}}
+  {{Type (printf "%s?" .Type)}} {{FieldName .Name}},
+  {{- end}}
+}) {
+  return {{$t.Name}}(
+    {{- range $t.Fields}}
+    {{FieldName .Name}}: {{FieldName .Name}} ?? {{DefaultOf .}},
+    {{- end}}
+  );
+}
+{{- end}}
+{{define "args"}}
+{{- range $i, $e := .InjectedBy "constructor" "options"}}{{if $i}}, {{end}}{{$e.FieldName | Ident}}{{end}}
+{{- end}}
+{{- define "tests"}}
+    {{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+    {{- if eq .Target.Kind "constructor"}}
+    {{- $call = printf "%s(%s)" .Target.ClassName (include "args" .)}}
+    {{- else if eq .Target.Kind "static"}}{{$call = printf "%s.%s()" .Target.ClassName (Ident .Target.MethodName)}}
+    {{- else if eq .Target.Kind "function"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}
+    {{- end}}
+    {{- if not .Scenarios}}
+    {{- if not .Target.Methods}}
+{{end}}
+    test('should execute correctly', () {{if .Target.Async}}async {{end}}{
+      // final result = {{if .Target.Async}}await {{end}}{{$call}};
+      // expect(result, isNotNull);
+    });
+    {{- end}}
+
+    {{- range $i, $s := .Scenarios}}
+    {{- if or $i (not $.Target.Methods)}}
+{{end}}
+    test({{$s.Description | Quote}}, () {{if $.Target.Async}}async {{end}}{
+      // Arrange
+      {{- range $name, $v := $s.Inputs}}
+      final {{VarName $name}} = {{FormatValue $v}};
+      {{- end}}
+      {{- range $m := $s.MocksSetup}}
+      {{- $sig := $.MethodOf .Dependency .Method}}
+      {{- if not $sig.Declared}}
+      when(() => {{.Dependency | Ident}}.{{.Method | Ident}}(any())){{if .Error}}.thenThrow(Exception({{.Error | Quote}})){{else}}.thenReturn({{.ReturnValue | FormatValue}}){{end}};
+      {{- else}}
+      when(() => {{.Dependency | Ident}}.{{.Method | Ident}}({{range $i, $p := $sig.Params}}{{if $i}}, {{end}}any(){{end}}))
+      {{- if and $sig.Async .Error}}.thenAnswer((_) => Future.error(Exception({{.Error | Quote}})))
+      {{- else if .Error}}.thenThrow(Exception({{.Error | Quote}}))
+      {{- else if $sig.Async}}.thenAnswer((_) async {{if $sig.Void}}{}{{else}}=> {{.ReturnValue | FormatAs $sig.Returns}}{{end}})
+      {{- else if $sig.Void}}.thenAnswer((_) {})
+      {{- else}}.thenReturn({{.ReturnValue | FormatAs $sig.Returns}})
+      {{- end}};
+      {{- end}}
+      {{- end}}
+
+      {{- if $s.Expectations.Error}}
+
+      // Act & Assert
+      {{- if $.Target.Async}}
+      // await expectLater({{$call}}, throwsA(isA<{{$s.Expectations.Error}}>()));
+      {{- else}}
+      // expect(() => {{$call}}, throwsA(isA<{{$s.Expectations.Error}}>()));
+      {{- end}}
+      {{- else}}
+
+      // Act
+      // final result = {{if $.Target.Async}}await {{end}}{{$call}};
+
+      // Assert
+      {{- if $s.Expectations.HasReturn}}
+      // expect(result, equals({{$s.Expectations.ReturnValue | FormatValue}}));
+      {{- end}}
+      {{- end}}
+    });
+    {{- end}}
+{{- end}}`
+
+// dartFake devolve o nome da classe Fake do mocktail para um tipo da aplicação
+// ("User" -> "FakeUser"), ou vazio para listas e mapas, que usam um literal.
+func dartFake(s string) string {
+	t, err := parseType(s)
+	if err != nil || t.name == "list" || t.name == "map" {
+		return ""
+	}
+	return "Fake" + strings.ReplaceAll(asciiType("dart", t).name, ".", "")
+}
+
+// dartFallback escreve o valor do registerFallbackValue: uma instância do Fake
+// ou uma coleção vazia com os tipos dos argumentos (<int>[], <String, int>{}).
+func dartFallback(s string) string {
+	if fake := dartFake(s); fake != "" {
+		return fake + "()"
+	}
+	t, err := parseType(s)
+	if err != nil {
+		return "null"
+	}
+	ts := typeSyntaxes["dart"]
+	args := make([]string, len(t.args))
+	for i, arg := range t.args {
+		args[i] = ts.render(asciiType("dart", arg), true)
+	}
+	if t.name == "list" {
+		return "<" + args[0] + ">[]"
+	}
+	return "<" + strings.Join(args, ", ") + ">{}"
+}
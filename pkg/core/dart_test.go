@@ -0,0 +1,27 @@
+package core
+
+import "testing"
+
+func TestDartFallbackValues(t *testing.T) {
+	code := generate(t, `{"target": {"class_name": "Svc", "method_name": "run"},
+	  "dependencies": [{"field_name": "repo", "interface_name": "Repo", "methods": [
+	    {"name": "save", "params": [{"name": "user", "type": "User"}, {"name": "id", "type": "int"}]},
+	    {"name": "saveAll", "params": [{"name": "users", "type": "list<User>"}, {"name": "owner", "type": "User?"}]},
+	    {"name": "tag", "params": [{"name": "tags", "type": "map<string, int>"}, {"name": "note", "type": "string?"}]}
+	  ]}],
+	  "scenarios": [{"id": "ok", "mocks_setup": [{"dependency": "repo", "method": "save"}]}]}`, "dart", "")
+	assertContains(t, code,
+		"class FakeUser extends Fake implements User {}",
+		"setUpAll(() {",
+		"registerFallbackValue(FakeUser());",
+		"registerFallbackValue(<User>[]);",
+		"registerFallbackValue(<String, int>{});",
+		"when(() => repo.save(any(), any()))",
+	)
+	assertNotContains(t, code, "registerFallbackValue(0)", "FakeList", "FakeString")
+
+	plain := generate(t, `{"target": {"class_name": "Svc", "method_name": "run"},
+	  "dependencies": [{"field_name": "repo", "interface_name": "Repo", "methods": [{"name": "count", "params": [{"name": "id", "type": "int"}]}]}],
+	  "scenarios": [{"id": "ok"}]}`, "dart", "")
+	assertNotContains(t, plain, "setUpAll", "registerFallbackValue", "extends Fake")
+}
@@ -0,0 +1,454 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// --- TIPOS DA APLICAÇÃO (DTOs) ---
+
+// TypeDef declara o formato de um DTO usado nos valores da spec. Objetos com
+// "$type" (ex: {"$type": "User", "id": 1}) viram a construção do tipo em cada
+// linguagem: &User{ID: 1}, new User { Id = 1 }, User(id = 1), new User(1)...
+type TypeDef struct {
+	Name    string  `json:"name"`
+	Fields  []Field `json:"fields"`  // Na ordem do construtor (Java, Swift...)
+	Builder bool    `json:"builder"` // Gera um helper de dados de teste para o tipo
+}
+
+type Field struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Default interface{} `json:"default"` // Valor do builder; vazio usa o valor zero do tipo
+}
+
+// typeKey marca, em um objeto da spec, o DTO que ele representa.
+const typeKey = "$type"
+
+// Builders lista os tipos que pediram helper de dados de teste, com o nome
+// já seguro para virar identificador.
+func (m MetaFramework) Builders() []TypeDef {
+	var builders []TypeDef
+	for _, def := range m.Types {
+		if def.Builder {
+			def.Name = sanitizeIdentFor(m.lang, def.Name)
+			builders = append(builders, def)
+		}
+	}
+	return builders
+}
+
+// checkTypeDefs valida nomes e tipos dos campos da seção "types".
+func (m MetaFramework) checkTypeDefs() error {
+	seen := map[string]bool{}
+	for _, def := range m.Types {
+		if strings.TrimSpace(def.Name) == "" {
+			return fmt.Errorf("%w: type without name", ErrInvalidType)
+		}
+		if seen[def.Name] {
+			return fmt.Errorf("%w: type %s declared twice", ErrInvalidType, def.Name)
+		}
+		seen[def.Name] = true
+		for _, field := range def.Fields {
+			if _, err := parseType(field.Type); err != nil {
+				return fmt.Errorf("%w in %s.%s: %v", ErrInvalidType, def.Name, field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// construction é um DTO pronto para ser escrito, com os campos na ordem da declaração.
+type construction struct {
+	name   string
+	fields []fieldValue
+	ref    bool // Go: &User{...}, usado quando o tipo esperado é um ponteiro ou desconhecido
+}
+
+type fieldValue struct {
+	name  string // Já na convenção da linguagem
+	value string
+	given bool // Informado no valor; os demais levam o valor zero do tipo
+}
+
+// given devolve só os campos informados, para linguagens com inicializadores parciais.
+func (c construction) given() []fieldValue {
+	var fields []fieldValue
+	for _, f := range c.fields {
+		if f.given {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// dtoSyntax descreve a construção de DTOs e os literais que dependem do tipo esperado.
+type dtoSyntax struct {
+	construct func(c construction) string
+	fieldCase string // Convenção dos nomes de campo: "pascal", "snake" ou "" (camelCase)
+
+	number    func(t, number string, integral bool) string // Literais numéricos tipados (1L, 1.5f)
+	some      func(v string) string                        // Valor presente em um opcional (Some(v))
+	owned     func(s string) string                        // String com dono ("a".to_string())
+	typedList func(elem string, items []string) string
+	typedMap  func(key, value string, keys, values []string) string
+	zeros     map[string]string // Valores zero fora do padrão (chave: tipo portável ou "optional")
+	pointers  bool              // Tipos declarados sempre por ponteiro, como os opcionais (Go: *User, &User{...})
+}
+
+// args junta os campos como argumentos nomeados ("id = 1") ou posicionais (sep vazio).
+func args(fields []fieldValue, sep string) string {
+	list := make([]string, len(fields))
+	for i, f := range fields {
+		if sep == "" {
+			list[i] = f.value
+		} else {
+			list[i] = f.name + sep + f.value
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// call escreve "<prefixo>Nome(<campos>)" com todos os campos, na ordem da declaração.
+func call(prefix, sep string) func(c construction) string {
+	return func(c construction) string {
+		return prefix + c.name + "(" + args(c.fields, sep) + ")"
+	}
+}
+
+func fractional(suffix string) func(t, number string, integral bool) string {
+	return func(t, number string, integral bool) string {
+		switch {
+		case t == "long" && integral:
+			return number + "L"
+		case t == "float":
+			return number + suffix
+		}
+		return number
+	}
+}
+
+var tsDTO = dtoSyntax{
+	construct: func(c construction) string {
+		if len(c.fields) == 0 {
+			return "{}"
+		}
+		return "{ " + args(c.fields, ": ") + " }"
+	},
+}
+
+var dtoSyntaxes = map[string]dtoSyntax{
+	"go": {
+		fieldCase: "pascal",
+		pointers:  true,
+		construct: func(c construction) string {
+			prefix := ""
+			if c.ref {
+				prefix = "&"
+			}
+			return prefix + c.name + "{" + args(c.given(), ": ") + "}"
+		},
+		typedList: func(elem string, items []string) string { return "[]" + elem + "{" + strings.Join(items, ", ") + "}" },
+		typedMap: func(key, value string, keys, values []string) string {
+			return "map[" + key + "]" + value + "{" + strings.Join(pairs(keys, values, quoted, ": "), ", ") + "}"
+		},
+	},
+	"csharp": {
+		fieldCase: "pascal",
+		construct: func(c construction) string {
+			if len(c.given()) == 0 {
+				return "new " + c.name + "()"
+			}
+			return "new " + c.name + " { " + args(c.given(), " = ") + " }"
+		},
+		number: func(t, number string, integral bool) string {
+			if t == "float" {
+				return number + "f"
+			}
+			return number
+		},
+		typedList: func(elem string, items []string) string {
+			if len(items) == 0 {
+				return "new List<" + elem + ">()"
+			}
+			return "new List<" + elem + "> { " + strings.Join(items, ", ") + " }"
+		},
+		typedMap: func(key, value string, keys, values []string) string {
+			if len(keys) == 0 {
+				return "new Dictionary<" + key + ", " + value + ">()"
+			}
+			entry := func(k string) string { return "[" + quoted(k) + "]" }
+			return "new Dictionary<" + key + ", " + value + "> { " + strings.Join(pairs(keys, values, entry, " = "), ", ") + " }"
+		},
+	},
+	"kotlin": {
+		construct: call("", " = "),
+		number: func(t, number string, integral bool) string {
+			switch {
+			case t == "double" && integral && !strings.ContainsAny(number, ".e"):
+				return number + ".0"
+			case t == "float":
+				return number + "f"
+			case t == "long":
+				return number + "L"
+			}
+			return number
+		},
+	},
+	"java":       {construct: call("new ", ""), number: fractional("f")},
+	"php":        {construct: call("new ", ": ")},
+	"typescript": tsDTO,
+	"node": {
+		construct: func(c construction) string {
+			if len(c.given()) == 0 {
+				return "{}"
+			}
+			return "{ " + args(c.given(), ": ") + " }"
+		},
+	},
+	"python": {fieldCase: "snake", construct: call("", "=")},
+	"rust": {
+		fieldCase: "snake",
+		construct: func(c construction) string {
+			if len(c.fields) == 0 {
+				return c.name + " {}"
+			}
+			return c.name + " { " + args(c.fields, ": ") + " }"
+		},
+		number: func(t, number string, integral bool) string {
+			if (t == "float" || t == "double") && integral && !strings.ContainsAny(number, ".e") {
+				return number + ".0"
+			}
+			return number
+		},
+		some:  func(v string) string { return "Some(" + v + ")" },
+		owned: func(s string) string { return s + ".to_string()" },
+		zeros: map[string]string{"any": "serde_json::Value::Null", "map": "HashMap::new()"},
+	},
+	"swift": {construct: call("", ": ")},
+	"ruby":  {fieldCase: "snake", construct: func(c construction) string { return c.name + ".new(" + args(c.fields, ": ") + ")" }},
+	"cpp": {
+		construct: func(c construction) string {
+			fields := c.given()
+			list := make([]string, len(fields))
+			for i, f := range fields {
+				list[i] = "." + f.name + " = " + f.value
+			}
+			return c.name + "{" + strings.Join(list, ", ") + "}"
+		},
+		// Return() do gMock não deduz o tipo de uma lista entre chaves
+		typedList: func(elem string, items []string) string {
+			return "std::vector<" + elem + ">{" + strings.Join(items, ", ") + "}"
+		},
+		typedMap: func(key, value string, keys, values []string) string {
+			entries := make([]string, len(keys))
+			for i := range keys {
+				entries[i] = "{" + quoted(keys[i]) + ", " + values[i] + "}"
+			}
+			return "std::map<" + key + ", " + value + ">{" + strings.Join(entries, ", ") + "}"
+		},
+		zeros: map[string]string{"optional": "std::nullopt"},
+	},
+	"dart": {construct: call("", ": ")},
+	"elixir": {
+		fieldCase: "snake",
+		construct: func(c construction) string {
+			return "%" + c.name + "{" + args(c.given(), ": ") + "}"
+		},
+	},
+	"scala": {
+		construct: call("", " = "),
+		number:    fractional("f"),
+		some:      func(v string) string { return "Some(" + v + ")" },
+		zeros:     map[string]string{"optional": "None"},
+	},
+}
+
+// hasTypedValues indica se o valor contém algum objeto com "$type" ou uma
+// expressão ("$new", "$date"...), que não cabem em um literal composto.
+func hasTypedValues(v interface{}) bool {
+	if _, _, ok := expressionKey(v); ok {
+		return true
+	}
+	switch val := v.(type) {
+	case []interface{}:
+		for _, item := range val {
+			if hasTypedValues(item) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		if _, ok := val[typeKey]; ok {
+			return true
+		}
+		for _, item := range val {
+			if hasTypedValues(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseType interpreta um tipo da spec; com "pointers", os tipos declarados
+// viram ponteiros (opcionais), para que retornos, campos, builders e valores
+// usem a mesma forma.
+func (vs valueSyntax) parseType(s string) (typeRef, error) {
+	t, err := parseType(s)
+	if err != nil || !vs.dto.pointers {
+		return t, err
+	}
+	var pointer func(t typeRef) typeRef
+	pointer = func(t typeRef) typeRef {
+		args := make([]typeRef, len(t.args))
+		for i, arg := range t.args {
+			args[i] = pointer(arg)
+		}
+		t.args = args
+		if _, declared := vs.types[t.name]; declared {
+			t.optional = true
+		}
+		return t
+	}
+	return pointer(t), nil
+}
+
+// typeText escreve um tipo da spec na linguagem (função Type dos templates).
+func (vs valueSyntax) typeText(s string) string {
+	ts, ok := typeSyntaxes[vs.lang]
+	t, err := vs.parseType(s)
+	if !ok || err != nil || strings.TrimSpace(s) == "" {
+		return typeName(vs.lang, s)
+	}
+	return ts.render(asciiType(vs.lang, t), false)
+}
+
+// typeName escreve um tipo já interpretado na linguagem.
+func (vs valueSyntax) typeName(t typeRef) string {
+	if ts, ok := typeSyntaxes[vs.lang]; ok {
+		return ts.render(asciiType(vs.lang, t), true)
+	}
+	return t.name
+}
+
+// fieldName escreve o nome de um campo na convenção da linguagem.
+func (vs valueSyntax) fieldName(name string) string {
+	switch vs.dto.fieldCase {
+	case "pascal":
+		name = vs.naming.pascal(name)
+	case "snake":
+		name = toSnake(name)
+	default:
+		name = vs.naming.camel(name)
+	}
+	return ident(vs.lang, name)
+}
+
+// construct escreve um objeto com "$type" (ou esperado como um tipo
+// declarado) como a construção do DTO.
+func (vs valueSyntax) construct(v interface{}, expected *typeRef) (string, bool) {
+	obj, ok := v.(map[string]interface{})
+	if !ok || vs.dto.construct == nil {
+		return "", false
+	}
+
+	name, _ := obj[typeKey].(string)
+	if name == "" && expected != nil {
+		if _, declared := vs.types[expected.name]; declared {
+			name = expected.name
+		}
+	}
+	if name == "" {
+		return "", false
+	}
+
+	c := construction{name: sanitizeIdentFor(vs.lang, name), ref: expected == nil || expected.optional}
+	used := map[string]bool{typeKey: true}
+	for _, field := range vs.types[name].Fields {
+		t, _ := vs.parseType(field.Type)
+		fv := fieldValue{name: vs.fieldName(field.Name)}
+		if value, given := obj[field.Name]; given {
+			fv.value, fv.given = vs.render(value, &t), true
+		} else {
+			fv.value = vs.zero(t, map[string]bool{name: true})
+		}
+		used[field.Name] = true
+		c.fields = append(c.fields, fv)
+	}
+
+	// Campos fora da declaração (ou de um tipo não declarado), em ordem alfabética
+	var extra []string
+	for key := range obj {
+		if !used[key] {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	for _, key := range extra {
+		c.fields = append(c.fields, fieldValue{name: vs.fieldName(key), value: vs.render(obj[key], nil), given: true})
+	}
+	return vs.dto.construct(c), true
+}
+
+// zero escreve o valor zero de um tipo; "seen" evita recursão em tipos que se referenciam.
+func (vs valueSyntax) zero(t typeRef, seen map[string]bool) string {
+	key := t.name
+	if t.optional {
+		key = "optional"
+	}
+	if literal, ok := vs.dto.zeros[key]; ok {
+		return literal
+	}
+
+	switch {
+	case t.optional || t.name == "any" || t.name == "void":
+		return vs.null
+	case t.name == "string":
+		return vs.render("", &t)
+	case t.name == "int" || t.name == "long" || t.name == "float" || t.name == "double":
+		return vs.render(0.0, &t)
+	case t.name == "bool":
+		return vs.no
+	case t.name == "list":
+		return vs.render([]interface{}{}, &t)
+	case t.name == "map":
+		return vs.render(map[string]interface{}{}, &t)
+	}
+
+	def, declared := vs.types[t.name]
+	if !declared || seen[t.name] {
+		return vs.null
+	}
+	seen[t.name] = true
+	defer delete(seen, t.name)
+
+	c := construction{name: sanitizeIdentFor(vs.lang, def.Name)}
+	for _, field := range def.Fields {
+		ft, _ := vs.parseType(field.Type)
+		c.fields = append(c.fields, fieldValue{name: vs.fieldName(field.Name), value: vs.zero(ft, seen)})
+	}
+	return vs.dto.construct(c)
+}
+
+// zeroOf escreve o valor zero de um tipo da spec (ex: o retorno de um mock
+// que falha no Go); sem tipo, usa o nulo da linguagem.
+func (vs valueSyntax) zeroOf(s string) string {
+	t, err := vs.parseType(s)
+	if err != nil || strings.TrimSpace(s) == "" {
+		return vs.null
+	}
+	return vs.zero(t, map[string]bool{})
+}
+
+// defaultOf escreve o valor padrão de um campo para os builders.
+func (vs valueSyntax) defaultOf(field Field) string {
+	t, err := vs.parseType(field.Type)
+	if err != nil {
+		return vs.format(field.Default)
+	}
+	if field.Default == nil {
+		return vs.zero(t, map[string]bool{})
+	}
+	return vs.render(field.Default, &t)
+}
@@ -0,0 +1,78 @@
+package core
+
+// TEMPLATE ELIXIR (ExUnit + Mox)
+// O Mox.defmock costuma ficar no test_helper.exs; aqui ele é gerado junto
+// do teste para o arquivo funcionar sozinho.
+const elixirTmpl = `{{- range .Dependencies}}Mox.defmock(Mock{{.InterfaceName}}, for: {{.InterfaceName}})
+{{end}}
+{{- if .Dependencies}}
+{{end -}}
+defmodule {{.Target.ClassName}}Test do
+  use ExUnit.Case, async: true
+  {{- if .Dependencies}}
+
+  import Mox
+
+  # The mocks use stub/3 so the tests pass while Act is commented out;
+  # switch to expect/4 once Act is live to verify each call
+  setup :verify_on_exit!
+  {{- end}}
+  {{- range $g := .Groups}}
+  {{- $name := ToSnake .Target.MethodName}}
+  {{- if eq .Target.Kind "constructor"}}{{$name = "new"}}{{end}}
+
+  describe {{$name | Quote}} do
+    {{- if not .Scenarios}}
+    test "should execute correctly" do
+      # result = {{.Target.ClassName}}.{{$name | Ident}}()
+      # assert result
+    end
+    {{- end}}
+
+    {{- range $i, $s := .Scenarios}}
+    {{- if $i}}
+{{end}}
+    test {{$s.Description | Quote}} do
+      # Arrange
+      {{- range $name, $v := $s.Inputs}}
+      {{VarName $name}} = {{FormatValue $v}}
+      {{- end}}
+      {{- range $m := $s.MocksSetup}}
+      {{- $sig := $.MethodOf .Dependency .Method}}
+      {{- if $sig.Declared}}
+      stub(Mock{{$.InterfaceOf .Dependency}}, :{{.Method | ToSnake}}, fn{{range $i, $p := $sig.Params}}{{if $i}},{{end}} _{{$p.Name | ToSnake}}{{end}} -> {{if .Error}}raise {{.Error | Quote}}{{else if $sig.Void}}:ok{{else}}{{.ReturnValue | FormatAs $sig.Returns}}{{end}} end)
+      {{- else}}
+      stub(Mock{{$.InterfaceOf .Dependency}}, :{{.Method | ToSnake}}, fn _ -> {{if .Error}}raise {{.Error | Quote}}{{else}}{{.ReturnValue | FormatValue}}{{end}} end)
+      {{- end}}
+      {{- end}}
+
+      {{- if $s.Expectations.Error}}
+
+      # Act & Assert
+      # assert_raise {{$s.Expectations.Error}}, fn -> {{$.Target.ClassName}}.{{$name | Ident}}() end
+      {{- else}}
+
+      # Act
+      # result = {{$.Target.ClassName}}.{{$name | Ident}}()
+
+      # Assert
+      {{- if $s.Expectations.HasReturn}}
+      # assert result == {{$s.Expectations.ReturnValue | FormatValue}}
+      {{- end}}
+      {{- end}}
+    end
+    {{- end}}
+  end
+  {{- end}}
+  {{- range $t := .Builders}}
+
+  defp build_{{$t.Name | ToSnake}}(attrs \\ %{}) do
+    struct!(%{{$t.Name}}{
+      {{- range $i, $f := $t.Fields}}{{if $i}},{{end}}
+      {{FieldName $f.Name}}: {{DefaultOf $f}}
+      {{- end}}
+    }, attrs)
+  end
+  {{- end}}
+end
+`
@@ -0,0 +1,17 @@
+package core
+
+import "testing"
+
+func TestElixirMocks(t *testing.T) {
+	code := generate(t, mockErrorSpec, "elixir", "")
+	assertContains(t, code,
+		"import Mox",
+		"setup :verify_on_exit!",
+		`stub(MockUserRepository, :find, fn _id -> raise "db down" end)`,
+	)
+	// Com o Act comentado, expect/4 falharia no verify_on_exit!
+	assertNotContains(t, code, "expect(")
+
+	plain := generate(t, `{"target": {"class_name": "Slug", "method_name": "make"}, "scenarios": [{"id": "ok", "description": "slugs"}]}`, "elixir", "")
+	assertNotContains(t, plain, "Mox", "verify_on_exit!")
+}
@@ -0,0 +1,541 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// --- EXPRESSÕES DE VALOR ---
+
+// Objetos da spec com uma destas chaves são expressões portáveis, escritas
+// no idioma de cada linguagem em vez de virarem um mapa:
+//
+//	{"$new": "Money", "args": [10, "USD"]}   construtor
+//	{"$enum": "Status.Active"}               membro de enum
+//	{"$const": "Config.MaxRetries"}          constante (dono opcional)
+//	{"$date": "2024-01-15T10:30:00Z"}        data (AAAA-MM-DD) ou instante (RFC 3339)
+//	{"$uuid": "123e4567-e89b-..."}           UUID
+//	{"$ref": "userId"}                       entrada do cenário ("inputs")
+//	{"$code": {"go": "...", "default": "..."}} código cru por linguagem
+const (
+	newKey   = "$new"
+	enumKey  = "$enum"
+	constKey = "$const"
+	dateKey  = "$date"
+	uuidKey  = "$uuid"
+	refKey   = "$ref"
+	codeKey  = "$code"
+)
+
+var expressionKeys = []string{newKey, enumKey, constKey, dateKey, uuidKey, refKey, codeKey}
+
+// ErrInvalidValue é devolvido (embrulhado) quando uma expressão da spec é inválida.
+var ErrInvalidValue = errors.New("invalid value")
+
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// expressionKey devolve a chave de expressão do objeto, se houver.
+func expressionKey(v interface{}) (string, map[string]interface{}, bool) {
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return "", nil, false
+	}
+	for _, key := range expressionKeys {
+		if _, ok := obj[key]; ok {
+			return key, obj, true
+		}
+	}
+	return "", nil, false
+}
+
+// parseDate aceita uma data (2024-01-15) ou um instante RFC 3339, normalizado para UTC.
+func parseDate(s string) (t time.Time, dateOnly bool, err error) {
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true, nil
+	}
+	t, err = time.Parse(time.RFC3339, s)
+	return t.UTC(), false, err
+}
+
+// splitOwner separa "Status.Active" em dono e nome; sem ponto, o dono fica vazio.
+func splitOwner(s string) (owner, name string) {
+	if i := strings.LastIndex(s, "."); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return "", s
+}
+
+// --- VALIDAÇÃO ---
+
+// checkValues valida as expressões de todos os cenários para a linguagem
+// (o "$code" precisa de uma versão para ela ou de um "default").
+func (m MetaFramework) checkValues(lang string) error {
+	for _, s := range m.Scenarios {
+		names := make([]string, 0, len(s.Inputs))
+		for name := range s.Inputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := checkValue(s.Inputs[name], lang, nil); err != nil {
+				return fmt.Errorf("scenario %s, input %s: %w", s.ID, name, err)
+			}
+		}
+		for _, mock := range s.MocksSetup {
+			if err := checkValue(mock.ReturnValue, lang, s.Inputs); err != nil {
+				return fmt.Errorf("scenario %s, %s.%s: %w", s.ID, mock.Dependency, mock.Method, err)
+			}
+		}
+		if err := checkValue(s.Expectations.ReturnValue, lang, s.Inputs); err != nil {
+			return fmt.Errorf("scenario %s, expectations: %w", s.ID, err)
+		}
+	}
+	return nil
+}
+
+// checkValue percorre o valor; "inputs" são as entradas que um "$ref" pode citar.
+func checkValue(v interface{}, lang string, inputs map[string]interface{}) error {
+	key, obj, ok := expressionKey(v)
+	if !ok {
+		switch val := v.(type) {
+		case []interface{}:
+			for _, item := range val {
+				if err := checkValue(item, lang, inputs); err != nil {
+					return err
+				}
+			}
+		case map[string]interface{}:
+			for _, item := range val {
+				if err := checkValue(item, lang, inputs); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	text, _ := obj[key].(string)
+	switch key {
+	case newKey:
+		if strings.TrimSpace(text) == "" {
+			return fmt.Errorf("%w: %s needs a type name", ErrInvalidValue, key)
+		}
+		args, ok := obj["args"].([]interface{})
+		if _, given := obj["args"]; given && !ok {
+			return fmt.Errorf("%w: %s args must be a list", ErrInvalidValue, key)
+		}
+		for _, arg := range args {
+			if err := checkValue(arg, lang, inputs); err != nil {
+				return err
+			}
+		}
+	case enumKey:
+		if owner, member := splitOwner(text); owner == "" || member == "" {
+			return fmt.Errorf("%w: %s %q must be Type.Member", ErrInvalidValue, key, text)
+		}
+	case constKey:
+		if _, name := splitOwner(text); name == "" {
+			return fmt.Errorf("%w: %s needs a name", ErrInvalidValue, key)
+		}
+	case dateKey:
+		if _, _, err := parseDate(text); err != nil {
+			return fmt.Errorf("%w: %s %q is not a date (2024-01-15) or RFC 3339 timestamp", ErrInvalidValue, key, text)
+		}
+	case uuidKey:
+		if !uuidRe.MatchString(text) {
+			return fmt.Errorf("%w: %s %q is not a UUID", ErrInvalidValue, key, text)
+		}
+	case refKey:
+		if _, ok := inputs[text]; !ok {
+			return fmt.Errorf("%w: %s %q is not a scenario input", ErrInvalidValue, key, text)
+		}
+	case codeKey:
+		if _, ok := codeFor(obj[key], lang); !ok {
+			return fmt.Errorf("%w: %s has no code for %s and no default", ErrInvalidValue, key, lang)
+		}
+	}
+	return nil
+}
+
+// codeFor escolhe o código cru da linguagem (chave canônica ou alias) ou o "default".
+func codeFor(v interface{}, lang string) (string, bool) {
+	versions, ok := v.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	fallback, hasDefault := versions["default"].(string)
+	for key, code := range versions {
+		if c, _ := CanonicalLang(key); c == lang {
+			if text, ok := code.(string); ok {
+				return text, true
+			}
+		}
+	}
+	return fallback, hasDefault
+}
+
+// --- SINTAXE POR LINGUAGEM ---
+
+// exprSyntax descreve como cada expressão vira código na linguagem.
+type exprSyntax struct {
+	newCall  func(name string, args []string) string
+	enum     func(owner, member string) string
+	constant func(owner, name string) string
+	date     func(t time.Time, dateOnly bool) string
+	uuid     func(s string) string // Vazio: o UUID vira uma string comum
+	varCase  string                // Convenção de variáveis: "snake" ou "" (camelCase)
+	sigil    string                // Prefixo de variáveis (ex: "$" no PHP)
+}
+
+// Construtores mais comuns
+func withNew(prefix, suffix string) func(name string, args []string) string {
+	return func(name string, args []string) string {
+		return prefix + name + suffix + "(" + strings.Join(args, ", ") + ")"
+	}
+}
+
+// qualified junta dono e nome com o separador da linguagem, convertendo o nome.
+func qualified(sep string, convert func(string) string) func(owner, name string) string {
+	return func(owner, name string) string {
+		if owner == "" {
+			return convert(name)
+		}
+		return owner + sep + convert(name)
+	}
+}
+
+func isoInstant(t time.Time) string { return t.Format("2006-01-02T15:04:05Z") }
+
+// javaTime serve Java, Kotlin e Scala, sempre com nomes qualificados (sem imports).
+func javaTime(t time.Time, dateOnly bool) string {
+	if dateOnly {
+		return fmt.Sprintf("java.time.LocalDate.of(%d, %d, %d)", t.Year(), t.Month(), t.Day())
+	}
+	return `java.time.Instant.parse("` + isoInstant(t) + `")`
+}
+
+func javaUUID(s string) string { return `java.util.UUID.fromString("` + s + `")` }
+
+var exprSyntaxes = map[string]exprSyntax{
+	"go": {
+		newCall:  withNew("New", ""),
+		enum:     func(owner, member string) string { return owner + toPascal(member) },
+		constant: qualified(".", toPascal),
+		date: func(t time.Time, dateOnly bool) string {
+			return fmt.Sprintf("time.Date(%d, time.%s, %d, %d, %d, %d, 0, time.UTC)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+		},
+		uuid: func(s string) string { return `uuid.MustParse("` + s + `")` },
+	},
+	"csharp": {
+		newCall:  withNew("new ", ""),
+		enum:     qualified(".", toPascal),
+		constant: qualified(".", toPascal),
+		date: func(t time.Time, dateOnly bool) string {
+			if dateOnly {
+				return fmt.Sprintf("new System.DateTime(%d, %d, %d)", t.Year(), t.Month(), t.Day())
+			}
+			return fmt.Sprintf("new System.DateTime(%d, %d, %d, %d, %d, %d, System.DateTimeKind.Utc)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+		},
+		uuid: func(s string) string { return `System.Guid.Parse("` + s + `")` },
+	},
+	"java": {
+		newCall:  withNew("new ", ""),
+		enum:     qualified(".", toScreamingSnake),
+		constant: qualified(".", toScreamingSnake),
+		date:     javaTime,
+		uuid:     javaUUID,
+	},
+	"kotlin": {
+		newCall:  withNew("", ""),
+		enum:     qualified(".", toScreamingSnake),
+		constant: qualified(".", toScreamingSnake),
+		date:     javaTime,
+		uuid:     javaUUID,
+	},
+	"scala": {
+		newCall:  withNew("new ", ""),
+		enum:     qualified(".", toPascal),
+		constant: qualified(".", toPascal),
+		date:     javaTime,
+		uuid:     javaUUID,
+	},
+	"typescript": jsExpr,
+	"node":       jsExpr,
+	"php": {
+		newCall:  withNew("new ", ""),
+		enum:     qualified("::", toPascal),
+		constant: qualified("::", toScreamingSnake),
+		date: func(t time.Time, dateOnly bool) string {
+			if dateOnly {
+				return `new \DateTimeImmutable("` + t.Format("2006-01-02") + `")`
+			}
+			return `new \DateTimeImmutable("` + isoInstant(t) + `")`
+		},
+		sigil: "$",
+	},
+	"python": {
+		newCall:  withNew("", ""),
+		enum:     qualified(".", toScreamingSnake),
+		constant: qualified(".", toScreamingSnake),
+		date: func(t time.Time, dateOnly bool) string {
+			if dateOnly {
+				return fmt.Sprintf("datetime.date(%d, %d, %d)", t.Year(), t.Month(), t.Day())
+			}
+			return fmt.Sprintf("datetime.datetime(%d, %d, %d, %d, %d, %d, tzinfo=datetime.timezone.utc)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+		},
+		uuid:    func(s string) string { return `uuid.UUID("` + s + `")` },
+		varCase: "snake",
+	},
+	"ruby": {
+		newCall:  withNew("", ".new"),
+		enum:     qualified("::", toScreamingSnake),
+		constant: qualified("::", toScreamingSnake),
+		date: func(t time.Time, dateOnly bool) string {
+			if dateOnly {
+				return fmt.Sprintf("Date.new(%d, %d, %d)", t.Year(), t.Month(), t.Day())
+			}
+			return fmt.Sprintf("Time.utc(%d, %d, %d, %d, %d, %d)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+		},
+		varCase: "snake",
+	},
+	"rust": {
+		newCall:  withNew("", "::new"),
+		enum:     qualified("::", toPascal),
+		constant: qualified("::", toScreamingSnake),
+		date: func(t time.Time, dateOnly bool) string {
+			if dateOnly {
+				return fmt.Sprintf("chrono::NaiveDate::from_ymd_opt(%d, %d, %d).unwrap()", t.Year(), t.Month(), t.Day())
+			}
+			return `"` + isoInstant(t) + `".parse::<chrono::DateTime<chrono::Utc>>().unwrap()`
+		},
+		uuid:    func(s string) string { return `uuid::Uuid::parse_str("` + s + `").unwrap()` },
+		varCase: "snake",
+	},
+	"swift": {
+		newCall:  withNew("", ""),
+		enum:     qualified(".", toCamel),
+		constant: qualified(".", toCamel),
+		date: func(t time.Time, dateOnly bool) string {
+			return `ISO8601DateFormatter().date(from: "` + isoInstant(t) + `")!`
+		},
+		uuid: func(s string) string { return `UUID(uuidString: "` + s + `")!` },
+	},
+	"dart": {
+		newCall:  withNew("", ""),
+		enum:     qualified(".", toCamel),
+		constant: qualified(".", toCamel),
+		date: func(t time.Time, dateOnly bool) string {
+			if dateOnly {
+				return fmt.Sprintf("DateTime.utc(%d, %d, %d)", t.Year(), t.Month(), t.Day())
+			}
+			return fmt.Sprintf("DateTime.utc(%d, %d, %d, %d, %d, %d)", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+		},
+	},
+	"cpp": {
+		newCall:  withNew("", ""),
+		enum:     qualified("::", toPascal),
+		constant: qualified("::", func(name string) string { return "k" + toPascal(name) }),
+		date: func(t time.Time, dateOnly bool) string {
+			day := fmt.Sprintf("std::chrono::sys_days{std::chrono::year{%d} / %d / %d}", t.Year(), t.Month(), t.Day())
+			if dateOnly {
+				return day
+			}
+			return fmt.Sprintf("%s + std::chrono::hours{%d} + std::chrono::minutes{%d} + std::chrono::seconds{%d}", day, t.Hour(), t.Minute(), t.Second())
+		},
+	},
+	"elixir": {
+		newCall: withNew("", ".new"),
+		enum:    func(owner, member string) string { return ":" + toSnake(member) },
+		constant: func(owner, name string) string {
+			if owner == "" {
+				return "@" + toSnake(name)
+			}
+			return owner + "." + toSnake(name) + "()"
+		},
+		date: func(t time.Time, dateOnly bool) string {
+			if dateOnly {
+				return "~D[" + t.Format("2006-01-02") + "]"
+			}
+			return "~U[" + t.Format("2006-01-02 15:04:05") + "Z]"
+		},
+		varCase: "snake",
+	},
+}
+
+var jsExpr = exprSyntax{
+	newCall:  withNew("new ", ""),
+	enum:     qualified(".", toPascal),
+	constant: qualified(".", toScreamingSnake),
+	date: func(t time.Time, dateOnly bool) string {
+		if dateOnly {
+			return `new Date("` + t.Format("2006-01-02") + `")`
+		}
+		return `new Date("` + isoInstant(t) + `")`
+	},
+}
+
+// varName escreve o nome de uma entrada do cenário como variável da linguagem.
+func (vs valueSyntax) varName(name string) string {
+	expr := exprSyntaxes[vs.lang]
+	if expr.varCase == "snake" {
+		name = toSnake(name)
+	} else {
+		name = vs.naming.camel(name)
+	}
+	return expr.sigil + ident(vs.lang, name)
+}
+
+// expression escreve um objeto de expressão ("$new", "$enum"...) na linguagem.
+// Expressões inválidas já foram recusadas por checkValues.
+func (vs valueSyntax) expression(v interface{}) (string, bool) {
+	key, obj, ok := expressionKey(v)
+	expr, known := exprSyntaxes[vs.lang]
+	if !ok || !known {
+		return "", false
+	}
+
+	text, _ := obj[key].(string)
+	switch key {
+	case newKey:
+		args, _ := obj["args"].([]interface{})
+		list := make([]string, len(args))
+		for i, arg := range args {
+			list[i] = vs.format(arg)
+		}
+		return expr.newCall(sanitizeIdentFor(vs.lang, text), list), true
+	case enumKey:
+		owner, member := splitOwner(text)
+		return expr.enum(owner, member), true
+	case constKey:
+		owner, name := splitOwner(text)
+		return expr.constant(owner, name), true
+	case dateKey:
+		t, dateOnly, _ := parseDate(text)
+		return expr.date(t, dateOnly), true
+	case uuidKey:
+		if expr.uuid == nil {
+			return vs.str(text), true
+		}
+		return expr.uuid(text), true
+	case refKey:
+		return vs.varName(text), true
+	case codeKey:
+		code, _ := codeFor(obj[key], vs.lang)
+		return code, true
+	}
+	return "", false
+}
+
+// Uses indica se algum valor da spec usa a expressão (ex: "date", "uuid"),
+// para os templates incluírem imports só quando necessário. "list" e "object"
+// indicam listas e mapas literais, inclusive campos list/map dos builders.
+func (m MetaFramework) Uses(kind string) bool {
+	key := "$" + kind
+	uses := func(v interface{}) bool { return usesExpression(v, key) }
+	if kind == "list" || kind == "object" {
+		uses = func(v interface{}) bool { return usesLiteral(v, kind) }
+		if m.buildersUse(kind) {
+			return true
+		}
+	}
+
+	for _, s := range m.Scenarios {
+		for _, input := range s.Inputs {
+			if uses(input) {
+				return true
+			}
+		}
+		for _, mock := range s.MocksSetup {
+			if uses(mock.ReturnValue) {
+				return true
+			}
+		}
+		if uses(s.Expectations.ReturnValue) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesLiteral procura listas ("list") ou mapas ("object") literais; objetos
+// de expressão e DTOs não contam, mas os argumentos e campos deles sim.
+func usesLiteral(v interface{}, kind string) bool {
+	var items []interface{}
+	switch val := v.(type) {
+	case []interface{}:
+		if kind == "list" {
+			return true
+		}
+		items = val
+	case map[string]interface{}:
+		if key, obj, ok := expressionKey(val); ok {
+			if key == newKey {
+				items, _ = obj["args"].([]interface{})
+			}
+			break
+		}
+		// Um objeto com "$type" é a construção de um DTO, não um mapa
+		if _, dto := val[typeKey]; kind == "object" && !dto {
+			return true
+		}
+		for _, item := range val {
+			items = append(items, item)
+		}
+	}
+	for _, item := range items {
+		if usesLiteral(item, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// buildersUse indica se algum campo dos builders tem o tipo list ("list") ou map ("object").
+func (m MetaFramework) buildersUse(kind string) bool {
+	name := map[string]string{"list": "list", "object": "map"}[kind]
+	var walk func(t typeRef) bool
+	walk = func(t typeRef) bool {
+		if t.name == name {
+			return true
+		}
+		for _, arg := range t.args {
+			if walk(arg) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, def := range m.Builders() {
+		for _, field := range def.Fields {
+			if t, err := parseType(field.Type); err == nil && walk(t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func usesExpression(v interface{}, key string) bool {
+	switch val := v.(type) {
+	case []interface{}:
+		for _, item := range val {
+			if usesExpression(item, key) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		if _, ok := val[key]; ok {
+			return true
+		}
+		for _, item := range val {
+			if usesExpression(item, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
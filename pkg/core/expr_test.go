@@ -0,0 +1,98 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// jsonValue decodifica um valor como ele chega da spec.
+func jsonValue(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("invalid JSON %s: %v", s, err)
+	}
+	return v
+}
+
+func TestCheckValue(t *testing.T) {
+	inputs := map[string]interface{}{"userId": 1}
+	tests := []struct {
+		name    string
+		value   string
+		lang    string
+		wantErr bool
+	}{
+		{"plain values", `{"id": 1, "tags": ["a", "b"], "ok": true}`, "go", false},
+		{"new with args", `{"$new": "Money", "args": [10, "USD"]}`, "go", false},
+		{"new without args", `{"$new": "Clock"}`, "go", false},
+		{"new without name", `{"$new": "", "args": [1]}`, "go", true},
+		{"new with non-list args", `{"$new": "Money", "args": 10}`, "go", true},
+		{"new with invalid arg", `{"$new": "Money", "args": [{"$uuid": "x"}]}`, "go", true},
+		{"enum", `{"$enum": "Status.Active"}`, "go", false},
+		{"enum without owner", `{"$enum": "Active"}`, "go", true},
+		{"enum without member", `{"$enum": "Status."}`, "go", true},
+		{"const without owner", `{"$const": "MaxRetries"}`, "go", false},
+		{"const without name", `{"$const": "Config."}`, "go", true},
+		{"date", `{"$date": "2024-01-15"}`, "go", false},
+		{"timestamp", `{"$date": "2024-01-15T10:30:00-03:00"}`, "go", false},
+		{"bad date", `{"$date": "15/01/2024"}`, "go", true},
+		{"uuid", `{"$uuid": "123e4567-e89b-12d3-a456-426614174000"}`, "go", false},
+		{"bad uuid", `{"$uuid": "123e4567"}`, "go", true},
+		{"ref to input", `{"$ref": "userId"}`, "go", false},
+		{"ref to unknown input", `{"$ref": "orderId"}`, "go", true},
+		{"code for language", `{"$code": {"go": "time.Now()"}}`, "go", false},
+		{"code by alias", `{"$code": {"golang": "time.Now()"}}`, "go", false},
+		{"code default", `{"$code": {"default": "now()"}}`, "python", false},
+		{"code without language or default", `{"$code": {"go": "time.Now()"}}`, "python", true},
+		{"code not an object", `{"$code": "time.Now()"}`, "go", true},
+		{"nested in list", `[1, {"user": {"$enum": "Active"}}]`, "go", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkValue(jsonValue(t, tt.value), tt.lang, inputs)
+			if tt.wantErr && !errors.Is(err, ErrInvalidValue) {
+				t.Errorf("checkValue(%s) = %v, want %v", tt.value, err, ErrInvalidValue)
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("checkValue(%s) = %v, want nil", tt.value, err)
+			}
+		})
+	}
+}
+
+func TestCheckValuesRefs(t *testing.T) {
+	ref := map[string]interface{}{"$ref": "userId"}
+	tests := []struct {
+		name     string
+		scenario Scenario
+		wantErr  bool
+	}{
+		{"expectation cites input", Scenario{
+			Inputs:       map[string]interface{}{"userId": 1},
+			Expectations: Expectation{ReturnValue: ref},
+		}, false},
+		{"mock cites input", Scenario{
+			Inputs:     map[string]interface{}{"userId": 1},
+			MocksSetup: []MockSetup{{Dependency: "repo", Method: "find", ReturnValue: ref}},
+		}, false},
+		{"input cites input", Scenario{
+			Inputs: map[string]interface{}{"userId": 1, "copy": ref},
+		}, true},
+		{"expectation cites another scenario", Scenario{
+			Expectations: Expectation{ReturnValue: ref},
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.scenario.ID = "s1"
+			err := MetaFramework{Scenarios: []Scenario{tt.scenario}}.checkValues("go")
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrInvalidValue)) {
+				t.Errorf("checkValues() = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
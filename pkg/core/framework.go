@@ -0,0 +1,202 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// --- FRAMEWORKS POR LINGUAGEM ---
+
+// Stack é o framework de teste (e de mocks) resolvido para uma linguagem.
+// Os templates leem .Stack.Runner e .Stack.Mock para escolher a variante.
+type Stack struct {
+	Runner string // ex: "xunit", "jest", "junit5"
+	Mock   string // ex: "moq"; vazio quando o runner já define os mocks
+}
+
+func (s Stack) String() string {
+	if s.Mock == "" {
+		return s.Runner
+	}
+	return s.Runner + "+" + s.Mock
+}
+
+// frameworkOptions lista as variantes aceitas por uma linguagem.
+// O primeiro item de cada lista é o padrão.
+type frameworkOptions struct {
+	runners []string
+	mocks   []string
+	aliases map[string]string // Nomes alternativos para runners, mocks ou combinações
+}
+
+var frameworks = map[string]frameworkOptions{
+	"csharp": {
+		runners: []string{"xunit", "nunit", "mstest"},
+		mocks:   []string{"nsubstitute", "moq", "fakeiteasy"},
+	},
+	"typescript": {
+		runners: []string{"jest", "vitest", "mocha"},
+		aliases: map[string]string{"mocha+sinon": "mocha", "sinon": "mocha"},
+	},
+	"node": {
+		runners: []string{"node:test", "jest"},
+		aliases: map[string]string{"node": "node:test", "native": "node:test"},
+	},
+	"java": {
+		runners: []string{"junit5", "junit4", "testng"},
+		aliases: map[string]string{"junit": "junit5", "jupiter": "junit5"},
+	},
+	"go": {
+		runners: []string{"testify", "gomock", "plain"},
+		aliases: map[string]string{"testing": "plain"},
+	},
+	"python": {
+		runners: []string{"unittest", "pytest"},
+	},
+	"scala": {
+		runners: []string{"flatspec", "funsuite"},
+		aliases: map[string]string{"anyflatspec": "flatspec", "anyfunsuite": "funsuite"},
+	},
+}
+
+// ErrUnsupportedFramework é devolvido (embrulhado) quando o framework pedido
+// não existe para a linguagem.
+var ErrUnsupportedFramework = errors.New("unsupported framework")
+
+// Frameworks descreve as variantes aceitas por uma linguagem
+// (ex: "xunit|nunit|mstest + nsubstitute|moq|fakeiteasy"). Vazio quando não há variantes.
+func Frameworks(lang string) string {
+	canonical, _ := CanonicalLang(lang)
+	opts, ok := frameworks[canonical]
+	if !ok {
+		return ""
+	}
+	text := strings.Join(opts.runners, "|")
+	if len(opts.mocks) > 0 {
+		text += " + " + strings.Join(opts.mocks, "|")
+	}
+	return text
+}
+
+// ResolveStack interpreta "framework" (ex: "nunit+moq", "vitest" ou "")
+// para a linguagem, completando o que faltar com os padrões.
+func ResolveStack(lang, framework string) (Stack, error) {
+	canonical, ok := CanonicalLang(lang)
+	if !ok {
+		return Stack{}, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, lang)
+	}
+
+	name := strings.ToLower(strings.TrimSpace(framework))
+	opts, ok := frameworks[canonical]
+	if !ok {
+		if name != "" {
+			return Stack{}, fmt.Errorf("%w: %s has no framework variants (got %q)", ErrUnsupportedFramework, canonical, framework)
+		}
+		return Stack{}, nil
+	}
+
+	var stack Stack
+	if alias, ok := opts.aliases[name]; ok {
+		name = alias
+	}
+	if name != "" {
+		for _, part := range strings.Split(name, "+") {
+			part = strings.TrimSpace(part)
+			if alias, ok := opts.aliases[part]; ok {
+				part = alias
+			}
+			switch {
+			case slices.Contains(opts.runners, part) && stack.Runner == "":
+				stack.Runner = part
+			case slices.Contains(opts.mocks, part) && stack.Mock == "":
+				stack.Mock = part
+			default:
+				return Stack{}, fmt.Errorf("%w for %s: %q (use %s)", ErrUnsupportedFramework, canonical, framework, Frameworks(canonical))
+			}
+		}
+	}
+
+	if stack.Runner == "" {
+		stack.Runner = opts.runners[0]
+	}
+	if stack.Mock == "" && len(opts.mocks) > 0 {
+		stack.Mock = opts.mocks[0]
+	}
+	return stack, nil
+}
+
+// KnowsFramework indica se a linguagem tem o framework entre as suas variantes
+// (ex: "nunit" no C#, mas não no TypeScript).
+func KnowsFramework(lang, framework string) bool {
+	canonical, _ := CanonicalLang(lang)
+	if _, ok := frameworks[canonical]; !ok || strings.TrimSpace(framework) == "" {
+		return false
+	}
+	_, err := ResolveStack(canonical, framework)
+	return err == nil
+}
+
+// CheckFramework valida um framework global: ele só é aplicado às linguagens
+// que o conhecem, então precisa existir em pelo menos uma delas.
+func CheckFramework(framework string) error {
+	for _, lang := range FrameworkLangs() {
+		if KnowsFramework(lang, framework) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no language has %q", ErrUnsupportedFramework, framework)
+}
+
+// FrameworkLangs devolve, em ordem, as linguagens canônicas com variantes de framework.
+func FrameworkLangs() []string {
+	langs := make([]string, 0, len(frameworks))
+	for lang := range frameworks {
+		langs = append(langs, lang)
+	}
+	slices.Sort(langs)
+	return langs
+}
+
+// ChoosesFramework indica se a spec escolhe o framework da linguagem, seja por
+// "frameworks", pelo antigo "style" do Scala ou por um "framework" global que ela conheça.
+func (m MetaInfo) ChoosesFramework(lang string) bool {
+	canonical, _ := CanonicalLang(lang)
+	for key := range m.Frameworks {
+		if c, _ := CanonicalLang(key); c == canonical {
+			return true
+		}
+	}
+	return (canonical == "scala" && m.Style != "") || KnowsFramework(canonical, m.Framework)
+}
+
+// StackFor resolve o framework de uma linguagem a partir da spec:
+// "frameworks" (por linguagem) tem prioridade sobre "framework" (global).
+// O "framework" global só vale para as linguagens que o conhecem (ex: "nunit"
+// no C# de uma spec C# + TypeScript); se nenhuma o conhece, é um erro.
+// O antigo "style" ainda vale para o Scala, abaixo de "frameworks".
+func (m MetaInfo) StackFor(lang string) (Stack, error) {
+	canonical, ok := CanonicalLang(lang)
+	if !ok {
+		return Stack{}, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, lang)
+	}
+
+	for key, framework := range m.Frameworks {
+		if c, _ := CanonicalLang(key); c == canonical {
+			return ResolveStack(canonical, framework)
+		}
+	}
+	if canonical == "scala" && m.Style != "" {
+		return ResolveStack(canonical, m.Style)
+	}
+	if KnowsFramework(canonical, m.Framework) {
+		return ResolveStack(canonical, m.Framework)
+	}
+	if strings.TrimSpace(m.Framework) != "" {
+		if err := CheckFramework(m.Framework); err != nil {
+			return Stack{}, err
+		}
+	}
+	return ResolveStack(canonical, "")
+}
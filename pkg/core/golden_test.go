@@ -0,0 +1,103 @@
+package core
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// Regrava os arquivos esperados: go test ./pkg/core -run Golden -update
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// Specs gravadas em testdata/golden/<dir>/spec.json; "" é a spec principal.
+// kinds: métodos static, function e constructor ao lado de um de instância;
+// injection, factory e container: as estratégias de injeção do SUT.
+var goldenDirs = []string{"", "kinds", "injection", "factory", "container"}
+
+// TestGolden gera cada spec em todas as linguagens e compara com a saída
+// gravada em testdata/golden/<dir>, com o framework padrão de cada uma.
+func TestGolden(t *testing.T) {
+	langs := make([]string, 0, len(extensions))
+	for lang := range extensions {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+
+	for _, dir := range goldenDirs {
+		spec := goldenSpec(t, dir)
+		for _, lang := range langs {
+			t.Run(filepath.Join(dir, lang), func(t *testing.T) {
+				got, err := ProcessTemplate(spec, lang)
+				if err != nil {
+					t.Fatalf("ProcessTemplate: %v", err)
+				}
+				checkGolden(t, filepath.Join("testdata", "golden", dir, lang+".golden"), got)
+			})
+		}
+	}
+}
+
+// TestGoldenFrameworks gera a spec principal nas variantes de framework que não
+// são o padrão da linguagem, comparando com testdata/golden/<lang>-<framework>.golden.
+func TestGoldenFrameworks(t *testing.T) {
+	spec := goldenSpec(t, "")
+	variants := map[string][]string{
+		"csharp":     {"nunit", "mstest", "moq", "fakeiteasy"},
+		"typescript": {"vitest", "mocha"},
+		"node":       {"jest"},
+		"java":       {"junit4", "testng"},
+		"go":         {"gomock", "plain"},
+		"python":     {"pytest"},
+		"scala":      {"funsuite"},
+	}
+
+	for lang, frameworks := range variants {
+		for _, framework := range frameworks {
+			name := lang + "-" + framework
+			t.Run(name, func(t *testing.T) {
+				spec.Meta.Frameworks = map[string]string{lang: framework}
+				got, err := ProcessTemplate(spec, lang)
+				if err != nil {
+					t.Fatalf("ProcessTemplate: %v", err)
+				}
+				checkGolden(t, filepath.Join("testdata", "golden", name+".golden"), got)
+			})
+		}
+	}
+}
+
+// goldenSpec decodifica testdata/golden/<dir>/spec.json.
+func goldenSpec(t *testing.T, dir string) MetaFramework {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "golden", dir, "spec.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec MetaFramework
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+// checkGolden compara o código com o arquivo esperado, ou o regrava com -update.
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s (run with -update after checking the diff):\n%s", path, got)
+	}
+}
@@ -0,0 +1,64 @@
+package core
+
+import "fmt"
+
+// --- INJEÇÃO DE DEPENDÊNCIAS ---
+
+// Estratégias de injeção: como o teste monta o SUT com os mocks.
+// No target valem todas; numa dependência, só as três primeiras.
+const (
+	InjectConstructor = "constructor" // Argumento do construtor (padrão)
+	InjectProperty    = "property"    // Atribuída (propriedade ou setter) depois da construção
+	InjectOptions     = "options"     // Functional option do Go (WithDb(db)); fora do Go, argumento
+	InjectFactory     = "factory"     // SUT criado por uma função de fábrica (target.factory)
+	InjectContainer   = "container"   // Mocks registrados num container de DI, que resolve o SUT
+)
+
+// withInjection valida e normaliza a injeção: o target fica com constructor,
+// factory ou container e cada dependência com constructor, property ou options.
+// "property" e "options" no target valem como padrão das dependências. Com
+// container, é ele quem injeta tudo: a injeção de uma dependência seria ignorada.
+func (m MetaFramework) withInjection() (MetaFramework, error) {
+	fallback := InjectConstructor
+	switch m.Target.Injection {
+	case "":
+		m.Target.Injection = InjectConstructor
+	case InjectConstructor, InjectFactory, InjectContainer:
+	case InjectProperty, InjectOptions:
+		fallback, m.Target.Injection = m.Target.Injection, InjectConstructor
+	default:
+		return m, fmt.Errorf("%w: injection %q (use constructor, property, options, factory or container)", ErrInvalidTarget, m.Target.Injection)
+	}
+
+	deps := make([]Dependency, len(m.Dependencies))
+	for i, dep := range m.Dependencies {
+		if dep.Injection != "" && m.Target.Injection == InjectContainer {
+			return m, fmt.Errorf("%w: dependency %q: injection %q has no effect with a container target", ErrInvalidTarget, dep.FieldName, dep.Injection)
+		}
+		switch dep.Injection {
+		case "":
+			dep.Injection = fallback
+		case InjectConstructor, InjectProperty, InjectOptions:
+		default:
+			return m, fmt.Errorf("%w: dependency %q: injection %q (use constructor, property or options)", ErrInvalidTarget, dep.FieldName, dep.Injection)
+		}
+		deps[i] = dep
+	}
+	m.Dependencies = deps
+	return m, nil
+}
+
+// InjectedBy devolve as dependências injetadas por uma das estratégias, na ordem
+// da spec. Ex: {{range .InjectedBy "property"}} para os setters depois do construtor.
+func (m MetaFramework) InjectedBy(styles ...string) []Dependency {
+	var deps []Dependency
+	for _, dep := range m.Dependencies {
+		for _, style := range styles {
+			if dep.Injection == style {
+				deps = append(deps, dep)
+				break
+			}
+		}
+	}
+	return deps
+}
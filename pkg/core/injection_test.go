@@ -0,0 +1,84 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithInjection(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		deps    []string // Injeção de cada dependência na spec
+		want    string   // Injeção normalizada do target
+		wantDep []string
+		wantErr bool
+	}{
+		{"default", "", []string{"", ""}, InjectConstructor, []string{InjectConstructor, InjectConstructor}, false},
+		{"property as default", InjectProperty, []string{"", InjectConstructor}, InjectConstructor, []string{InjectProperty, InjectConstructor}, false},
+		{"options as default", InjectOptions, []string{"", InjectProperty}, InjectConstructor, []string{InjectOptions, InjectProperty}, false},
+		{"factory with override", InjectFactory, []string{"", InjectProperty}, InjectFactory, []string{InjectConstructor, InjectProperty}, false},
+		{"container", InjectContainer, []string{"", ""}, InjectContainer, []string{InjectConstructor, InjectConstructor}, false},
+		{"override under container", InjectContainer, []string{"", InjectProperty}, "", nil, true},
+		{"unknown target style", "setter", nil, "", nil, true},
+		{"factory on dependency", "", []string{InjectFactory}, "", nil, true},
+		{"container on dependency", "", []string{InjectContainer}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := MetaFramework{Target: TargetInfo{Injection: tt.target}}
+			for i, injection := range tt.deps {
+				spec.Dependencies = append(spec.Dependencies, Dependency{FieldName: string(rune('a' + i)), Injection: injection})
+			}
+
+			got, err := spec.withInjection()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTarget) {
+					t.Fatalf("withInjection() = %v, want %v", err, ErrInvalidTarget)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Target.Injection != tt.want {
+				t.Errorf("Target.Injection = %q, want %q", got.Target.Injection, tt.want)
+			}
+			var deps []string
+			for _, dep := range got.Dependencies {
+				deps = append(deps, dep.Injection)
+			}
+			if !reflect.DeepEqual(deps, tt.wantDep) {
+				t.Errorf("dependency injections = %q, want %q", deps, tt.wantDep)
+			}
+		})
+	}
+}
+
+func TestInjectedBy(t *testing.T) {
+	spec := MetaFramework{Dependencies: []Dependency{
+		{FieldName: "db", Injection: InjectConstructor},
+		{FieldName: "mailer", Injection: InjectProperty},
+		{FieldName: "clock", Injection: InjectOptions},
+	}}
+	tests := []struct {
+		styles []string
+		want   []string
+	}{
+		{[]string{InjectProperty}, []string{"mailer"}},
+		{[]string{InjectOptions, InjectConstructor}, []string{"db", "clock"}},
+		{[]string{InjectFactory}, nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, dep := range spec.InjectedBy(tt.styles...) {
+			got = append(got, dep.FieldName)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("InjectedBy(%q) = %q, want %q", tt.styles, got, tt.want)
+		}
+	}
+}
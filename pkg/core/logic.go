@@ -1,11 +1,22 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"sync"
 	"text/template"
 )
 
+// Version é a versão do gerador. Entra no cache incremental da CLI,
+// então deve mudar sempre que a saída dos templates mudar.
+const Version = "1.4.0"
+
+// RendererVersion é a versão do código que escreve valores, DTOs e expressões
+// (values.go, dto.go, expr.go). Também entra no cache: deve mudar quando essa
+// saída mudar sem que o texto dos templates mude.
+const RendererVersion = 2
+
 // --- ESTRUTURAS DE DADOS (Igual ao seu original) ---
 
 type MetaFramework struct {
@@ -13,69 +24,232 @@ type MetaFramework struct {
 	Target       TargetInfo   `json:"target"`
 	Dependencies []Dependency `json:"dependencies"`
 	Scenarios    []Scenario   `json:"scenarios"`
+	Types        []TypeDef    `json:"types"` // DTOs usados nos valores ({"$type": "User", ...})
+
+	// Stack é preenchido por ProcessTemplate com o framework da linguagem gerada
+	Stack Stack  `json:"-"`
+	lang  string // Linguagem canônica gerada, também preenchida por ProcessTemplate
+}
+
+// Languages devolve a lista de linguagens pedidas pela spec,
+// priorizando "langs" e caindo para o campo legado "lang".
+func (m MetaFramework) Languages() []string {
+	if len(m.Meta.Langs) > 0 {
+		return m.Meta.Langs
+	}
+	if m.Meta.Lang != "" {
+		return []string{m.Meta.Lang}
+	}
+	return nil
+}
+
+// InterfaceOf devolve a interface da dependência declarada com "fieldName".
+// Quando a dependência não existe, usa o próprio nome do campo.
+func (m MetaFramework) InterfaceOf(fieldName string) string {
+	for _, dep := range m.Dependencies {
+		if dep.FieldName == fieldName {
+			return dep.InterfaceName
+		}
+	}
+	return fieldName
+}
+
+// MockedCalls devolve, para uma dependência, o primeiro "mocks_setup" de cada
+// método, na ordem em que aparecem nos cenários.
+func (m MetaFramework) MockedCalls(fieldName string) []MockSetup {
+	var calls []MockSetup
+	seen := map[string]bool{}
+	for _, s := range m.Scenarios {
+		for _, mock := range s.MocksSetup {
+			if mock.Dependency == fieldName && !seen[mock.Method] {
+				seen[mock.Method] = true
+				calls = append(calls, mock)
+			}
+		}
+	}
+	return calls
+}
+
+// MockedMethods lista, sem repetição, os métodos configurados para uma dependência.
+func (m MetaFramework) MockedMethods(fieldName string) []string {
+	var methods []string
+	for _, call := range m.MockedCalls(fieldName) {
+		methods = append(methods, call.Method)
+	}
+	return methods
+}
+
+// FailingMocks indica algum mock configurado com "error", para os templates
+// incluírem o que a falha usa (ex: "errors" no Go, <stdexcept> no C++).
+func (m MetaFramework) FailingMocks() bool {
+	for _, s := range m.allScenarios() {
+		for _, mock := range s.MocksSetup {
+			if mock.Error != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExpectsError indica algum cenário que espera um erro do alvo.
+func (m MetaFramework) ExpectsError() bool {
+	for _, s := range m.allScenarios() {
+		if s.Expectations.Error != "" {
+			return true
+		}
+	}
+	return false
 }
 
 type MetaInfo struct {
 	Lang  string   `json:"lang"`
 	Langs []string `json:"langs"`
+	// Framework de teste: "framework" vale para todas as linguagens da spec,
+	// "frameworks" escolhe por linguagem (ex: {"csharp": "nunit+moq", "ts": "vitest"})
+	Framework  string            `json:"framework"`
+	Frameworks map[string]string `json:"frameworks"`
+	Style      string            `json:"style"` // Obsoleto: o mesmo que frameworks.scala
+	// Convenções de nomes por linguagem (ex: {"go": {"acronyms": "capitalize"}})
+	Naming map[string]NamingOptions `json:"naming"`
 }
 
 type TargetInfo struct {
 	ClassName  string `json:"class_name"`
 	MethodName string `json:"method_name"`
+	Async      bool   `json:"async"` // Devolve Promise/Task/Future ou é suspend (Kotlin)
+	Kind       string `json:"kind"`  // instance (padrão), static, function ou constructor
+	// Como o SUT recebe os mocks (ver withInjection) e, com "factory", a função que o cria
+	Injection string `json:"injection"`
+	Factory   string `json:"factory"` // Vazio usa create<Classe>
+	// Vários métodos no mesmo arquivo, cada um com seus cenários (ver withTargetMethods)
+	Methods []TargetMethod `json:"methods"`
 }
 
 type Dependency struct {
-	FieldName     string `json:"field_name"`
-	InterfaceName string `json:"interface_name"`
+	FieldName     string   `json:"field_name"`
+	InterfaceName string   `json:"interface_name"`
+	Methods       []Method `json:"methods"`   // Opcional: assinaturas tipadas para mocks tipados
+	Injection     string   `json:"injection"` // Vazio herda a injeção do target
 }
 
 type Scenario struct {
-	ID           string      `json:"id"`
-	Description  string      `json:"description"`
-	MocksSetup   []MockSetup `json:"mocks_setup"`
-	Expectations Expectation `json:"expectations"`
+	ID           string                 `json:"id"`
+	Description  string                 `json:"description"`
+	Inputs       map[string]interface{} `json:"inputs"` // Variáveis do Arrange, citadas com {"$ref": "nome"}
+	MocksSetup   []MockSetup            `json:"mocks_setup"`
+	Expectations Expectation            `json:"expectations"`
 }
 
 type MockSetup struct {
 	Dependency  string      `json:"dependency"`
 	Method      string      `json:"method"`
 	ReturnValue interface{} `json:"return_value"`
+	Error       string      `json:"error"` // Mensagem do erro lançado (ou da Promise rejeitada) pelo mock
 }
 
 type Expectation struct {
 	ReturnValue interface{} `json:"return_value"`
+	Error       string      `json:"error"` // Erro esperado (ex: "AuthError.invalidCredentials")
+}
+
+// HasReturn informa se o cenário compara o retorno: false, 0 e "" também são
+// valores esperados, que o {{if}} dos templates trataria como ausentes.
+func (e Expectation) HasReturn() bool {
+	return e.ReturnValue != nil
 }
 
 // --- 2. TEMPLATES MULTI-LINGUAGEM ---
 
-// TEMPLATE GO
-const goTmpl = `package {{.Target.ClassName | ToLower}}
+// TEMPLATE GO (testify | gomock | testing puro)
+const goTmpl = `{{if eq .Stack.Runner "gomock"}}{{template "gomock" .}}` +
+	`{{else if eq .Stack.Runner "plain"}}{{template "plain" .}}` +
+	`{{else}}{{template "testify" .}}{{end}}` +
+	`{{define "testify"}}` + goTestifyTmpl + `{{end}}` +
+	`{{define "gomock"}}` + goMockTmpl + `{{end}}` +
+	`{{define "plain"}}` + goPlainTmpl + `{{end}}` +
+	`{{define "builders"}}` + goBuildersTmpl + `{{end}}` +
+	`{{define "args"}}` + goArgsTmpl + `{{end}}` +
+	`{{define "sut"}}` + goSutTmpl + `{{end}}` +
+	`{{define "results"}}` + goResultsTmpl + `{{end}}`
+
+const goTestifyTmpl = `package {{.Target.ClassName | ToLower}}
 
 import (
+	{{- if .FailingMocks}}
+	"errors"
+	{{- end}}
 	"testing"
-	"github.com/stretchr/testify/assert"
+	{{- if .Uses "date"}}
+	"time"
+	{{- end}}
+
+	{{if .Uses "uuid"}}"github.com/google/uuid"
+	{{end}}"github.com/stretchr/testify/assert"
+	{{- if .Dependencies}}
 	"github.com/stretchr/testify/mock"
+	{{- end}}
+	{{- if .ExpectsError}}
+	"github.com/stretchr/testify/require"
+	{{- end}}
 )
 
+// Used by the commented Assert steps
+{{- if .ExpectsError}}
+var (
+	_ = assert.Equal
+	_ = require.Error
+)
+{{- else}}
+var _ = assert.Equal
+{{- end}}
+
 {{- if .Dependencies}}
+
 // Mocks Definitions
-{{- range .Dependencies}}
-type Mock{{.InterfaceName}} struct {
+{{- range $i, $dep := .Dependencies}}
+{{- if $i}}
+{{end}}
+type Mock{{$dep.InterfaceName}} struct {
 	mock.Mock
 }
+{{- range $method := $.MethodsOf $dep.FieldName}}
+{{- if $method.Declared}}
+
+func (m *Mock{{$dep.InterfaceName}}) {{$method.Name | Ident}}({{Params $method.Params}}){{template "results" $method}} {
+	{{if or (not $method.Void) $method.Fails}}args := {{end}}m.Called({{range $i, $p := $method.Params}}{{if $i}}, {{end}}{{$p.Name | Ident}}{{end}})
+	{{- if $method.Void}}
+	{{- if $method.Fails}}
+	return args.Error(0)
+	{{- end}}
+	{{- else if eq (Type $method.Returns) "any"}}
+	return args.Get(0){{if $method.Fails}}, args.Error(1){{end}}
+	{{- else}}
+	result, _ := args.Get(0).({{Type $method.Returns}})
+	return result{{if $method.Fails}}, args.Error(1){{end}}
+	{{- end}}
+}
 {{- end}}
 {{- end}}
+{{- end}}
+{{- end}}
+{{- range $g := .Groups}}
 
-func Test{{.Target.MethodName}}(t *testing.T) {
+func Test{{if and (eq .Target.Kind "constructor") (not .Target.MethodName)}}New{{.Target.ClassName}}{{else}}{{.Target.MethodName | ToPascal}}{{end}}(t *testing.T) {
+	{{- $deps := include "args" .}}
+	{{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+	{{- if eq .Target.Kind "constructor"}}{{$call = printf "New%s(%s)" .Target.ClassName $deps}}
+	{{- else if ne .Target.Kind "instance"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}{{end}}
 	{{- if not .Scenarios}}
 	// Simple test case
 	t.Run("should work correctly", func(t *testing.T) {
 		// Arrange
+		{{- if eq .Target.Kind "instance"}}
 		// sut := New{{.Target.ClassName}}()
+		{{- end}}
 
 		// Act
-		// result := sut.{{.Target.MethodName}}()
+		// result := {{$call}}
 
 		// Assert
 		// assert.NotNil(t, result)
@@ -83,211 +257,896 @@ func Test{{.Target.MethodName}}(t *testing.T) {
 	{{- end}}
 
 	{{- range $s := .Scenarios}}
-	t.Run("{{$s.Description}}", func(t *testing.T) {
+	t.Run({{$s.Description | Quote}}, func(t *testing.T) {
+		// Arrange
+		{{- range $name, $v := $s.Inputs}}
+		{{VarName $name}} := {{FormatValue $v}}
+		{{- end}}
+		{{- range $dep := $.Dependencies}}
+		{{$dep.FieldName | Ident}} := new(Mock{{$dep.InterfaceName}})
+		{{- end}}
+
+		{{- range $m := $s.MocksSetup}}
+		{{- $sig := $.MethodOf .Dependency .Method}}
+		{{- $ret := ""}}
+		{{- if .Error}}{{$ret = printf "errors.New(%s)" (Quote .Error)}}{{if not $sig.Void}}{{$ret = printf "%s, %s" (ZeroOf $sig.Returns) $ret}}{{end}}
+		{{- else if not $sig.Void}}{{$ret = FormatAs $sig.Returns .ReturnValue}}{{if $sig.Fails}}{{$ret = printf "%s, nil" $ret}}{{end}}
+		{{- else if $sig.Fails}}{{$ret = "nil"}}{{end}}
+		{{.Dependency | Ident}}.On({{.Method | Ident | Quote}}{{range $sig.Params}}, mock.Anything{{end}}).Return({{$ret}})
+		{{- end}}
+		{{- if $s.Expectations.HasReturn}}
+		want := {{$s.Expectations.ReturnValue | FormatValue}}
+		{{- end}}
+
+		{{- if eq $g.Target.Kind "instance"}}
+{{template "sut" $g}}
+		{{- end}}
+		{{- $blanks := ""}}{{$vars := ""}}
+		{{- range $name, $v := $s.Inputs}}{{$blanks = printf "%s, _" $blanks}}{{$vars = printf "%s, %s" $vars (VarName $name)}}{{end}}
+		{{- range $.Dependencies}}{{$blanks = printf "%s, _" $blanks}}{{$vars = printf "%s, %s" $vars (Ident .FieldName)}}{{end}}
+		{{- if $s.Expectations.HasReturn}}{{$blanks = printf "%s, _" $blanks}}{{$vars = printf "%s, want" $vars}}{{end}}
+		{{- if $vars}}
+		{{slice $blanks 2}} = {{slice $vars 2}} // Used by the commented Act and Assert steps
+		{{- end}}
+
+		// Act
+		// {{if $s.Expectations.Error}}_, err{{else}}result{{end}} := {{$call}}
+
+		// Assert
+		{{- if $s.Expectations.Error}}
+		// require.Error(t, err) // Expected: {{$s.Expectations.Error | Comment}}
+		{{- else if $s.Expectations.HasReturn}}
+		// assert.Equal(t, want, result)
+		{{- end}}
+	})
+	{{- end}}
+}
+{{- end}}{{template "builders" .}}`
+
+// Os mocks do gomock são gerados pelo mockgen, fora do arquivo de teste
+const goMockTmpl = `package {{.Target.ClassName | ToLower}}
+
+import (
+	{{- if .FailingMocks}}
+	"errors"
+	{{- end}}
+	"testing"
+	{{- if .Uses "date"}}
+	"time"
+	{{- end}}
+	{{- if or (.Uses "uuid") .Dependencies}}
+{{end}}
+	{{- if .Uses "uuid"}}
+	"github.com/google/uuid"
+	{{- end}}
+	{{- if .Dependencies}}
+	"go.uber.org/mock/gomock"
+	{{- end}}
+)
+
+{{- if .Dependencies}}
+
+// Generate the mocks with:
+{{- range .Dependencies}}
+//   mockgen -destination=mock_{{.InterfaceName | ToSnake}}_test.go -package={{$.Target.ClassName | ToLower}} . {{.InterfaceName}}
+{{- end}}
+{{- end}}
+{{- range $g := .Groups}}
+
+func Test{{if and (eq .Target.Kind "constructor") (not .Target.MethodName)}}New{{.Target.ClassName}}{{else}}{{.Target.MethodName | ToPascal}}{{end}}(t *testing.T) {
+	{{- $deps := include "args" .}}
+	{{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+	{{- if eq .Target.Kind "constructor"}}{{$call = printf "New%s(%s)" .Target.ClassName $deps}}
+	{{- else if ne .Target.Kind "instance"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}{{end}}
+	{{- if not .Scenarios}}
+	t.Run("should work correctly", func(t *testing.T) {
+		{{- if eq .Target.Kind "instance"}}
+		// sut := New{{.Target.ClassName}}()
+		{{- end}}
+		// result := {{$call}}
+		// if result == nil {
+		// 	t.Fatal("expected a result")
+		// }
+	})
+	{{- end}}
+
+	{{- range $s := .Scenarios}}
+	t.Run({{$s.Description | Quote}}, func(t *testing.T) {
+		// Arrange
+		{{- range $name, $v := $s.Inputs}}
+		{{VarName $name}} := {{FormatValue $v}}
+		{{- end}}
+		{{- if $.Dependencies}}
+		ctrl := gomock.NewController(t)
+		{{- end}}
+		{{- range $dep := $.Dependencies}}
+		{{$dep.FieldName | Ident}} := NewMock{{$dep.InterfaceName}}(ctrl)
+		{{- end}}
+
+		{{- range $m := $s.MocksSetup}}
+		{{- $sig := $.MethodOf .Dependency .Method}}
+		{{- $ret := ""}}
+		{{- if .Error}}{{$ret = printf "errors.New(%s)" (Quote .Error)}}{{if not $sig.Void}}{{$ret = printf "%s, %s" (ZeroOf $sig.Returns) $ret}}{{end}}
+		{{- else if not $sig.Void}}{{$ret = FormatAs $sig.Returns .ReturnValue}}{{if $sig.Fails}}{{$ret = printf "%s, nil" $ret}}{{end}}
+		{{- else if $sig.Fails}}{{$ret = "nil"}}{{end}}
+		{{.Dependency | Ident}}.EXPECT().{{.Method | Ident}}({{if $sig.Declared}}{{range $i, $p := $sig.Params}}{{if $i}}, {{end}}gomock.Any(){{end}}{{else}}gomock.Any(){{end}}){{if $ret}}.Return({{$ret}}){{end}}
+		{{- end}}
+		{{- if $s.Expectations.HasReturn}}
+		want := {{$s.Expectations.ReturnValue | FormatValue}}
+		{{- end}}
+
+		{{- if eq $g.Target.Kind "instance"}}
+{{template "sut" $g}}
+		{{- end}}
+		{{- $blanks := ""}}{{$vars := ""}}
+		{{- range $name, $v := $s.Inputs}}{{$blanks = printf "%s, _" $blanks}}{{$vars = printf "%s, %s" $vars (VarName $name)}}{{end}}
+		{{- range $.Dependencies}}{{$blanks = printf "%s, _" $blanks}}{{$vars = printf "%s, %s" $vars (Ident .FieldName)}}{{end}}
+		{{- if $s.Expectations.HasReturn}}{{$blanks = printf "%s, _" $blanks}}{{$vars = printf "%s, want" $vars}}{{end}}
+		{{- if $vars}}
+		{{slice $blanks 2}} = {{slice $vars 2}} // Used by the commented Act and Assert steps
+		{{- end}}
+
+		// Act
+		// {{if $s.Expectations.Error}}_, err{{else}}result{{end}} := {{$call}}
+
+		// Assert
+		{{- if $s.Expectations.Error}}
+		// if err == nil {
+		// 	t.Fatal({{printf "expected %s" $s.Expectations.Error | Quote}})
+		// }
+		{{- else if $s.Expectations.HasReturn}}
+		// if result != want {
+		// 	t.Errorf("got %v, want %v", result, want)
+		// }
+		{{- end}}
+	})
+	{{- end}}
+}
+{{- end}}{{template "builders" .}}`
+
+// No modo "plain" os dublês são structs escritas à mão, sem dependências externas
+const goPlainTmpl = `package {{.Target.ClassName | ToLower}}
+
+{{if or .FailingMocks (.Uses "date") (.Uses "uuid") -}}
+import (
+	{{- if .FailingMocks}}
+	"errors"
+	{{- end}}
+	"testing"
+	{{- if .Uses "date"}}
+	"time"
+	{{- end}}
+	{{- if .Uses "uuid"}}
+
+	"github.com/google/uuid"
+	{{- end}}
+)
+{{- else -}}
+import "testing"
+{{- end}}
+
+{{- range $dep := .Dependencies}}
+
+// stub{{$dep.InterfaceName}} is a hand-written {{$dep.InterfaceName}} test double.
+type stub{{$dep.InterfaceName}} struct {
+	{{- range $method := $.MethodsOf $dep.FieldName}}
+	{{- if not $method.Void}}
+	{{$method.Name}}Result {{if $method.Declared}}{{Type $method.Returns}}{{else}}any{{end}}
+	{{- end}}
+	{{- if $method.Fails}}
+	{{$method.Name}}Err error
+	{{- end}}
+	{{- end}}
+}
+{{- range $method := $.MethodsOf $dep.FieldName}}
+{{- if $method.Declared}}
+
+func (s *stub{{$dep.InterfaceName}}) {{$method.Name | Ident}}({{Params $method.Params}}){{template "results" $method}} {
+	{{- if not $method.Void}}
+	return s.{{$method.Name}}Result{{if $method.Fails}}, s.{{$method.Name}}Err{{end}}
+	{{- else if $method.Fails}}
+	return s.{{$method.Name}}Err
+	{{- end}}
+}
+{{- else}}
+
+// Adjust the signature to match {{$dep.InterfaceName}}.{{$method.Name}}
+func (s *stub{{$dep.InterfaceName}}) {{$method.Name | Ident}}() {{if $method.Fails}}(any, error){{else}}any{{end}} {
+	return s.{{$method.Name}}Result{{if $method.Fails}}, s.{{$method.Name}}Err{{end}}
+}
+{{- end}}
+{{- end}}
+{{- end}}
+{{- range $g := .Groups}}
+
+func Test{{if and (eq .Target.Kind "constructor") (not .Target.MethodName)}}New{{.Target.ClassName}}{{else}}{{.Target.MethodName | ToPascal}}{{end}}(t *testing.T) {
+	{{- $deps := include "args" .}}
+	{{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+	{{- if eq .Target.Kind "constructor"}}{{$call = printf "New%s(%s)" .Target.ClassName $deps}}
+	{{- else if ne .Target.Kind "instance"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}{{end}}
+	{{- if not .Scenarios}}
+	t.Run("should work correctly", func(t *testing.T) {
+		{{- if eq .Target.Kind "instance"}}
+		// sut := New{{.Target.ClassName}}()
+		{{- end}}
+		// result := {{$call}}
+		// if result == nil {
+		// 	t.Fatal("expected a result")
+		// }
+	})
+	{{- end}}
+
+	{{- range $s := .Scenarios}}
+	t.Run({{$s.Description | Quote}}, func(t *testing.T) {
 		// Arrange
+		{{- range $name, $v := $s.Inputs}}
+		{{VarName $name}} := {{FormatValue $v}}
+		{{- end}}
 		{{- range $dep := $.Dependencies}}
-		// mock{{$dep.FieldName}} := new(Mock{{$dep.InterfaceName}})
+		{{$dep.FieldName | Ident}} := &stub{{$dep.InterfaceName}}{}
 		{{- end}}
 
 		{{- range $m := $s.MocksSetup}}
-		// {{.Dependency}}.On("{{.Method}}").Return({{.ReturnValue | FormatValue}})
+		{{- $sig := $.MethodOf .Dependency .Method}}
+		{{- if .Error}}
+		{{.Dependency | Ident}}.{{.Method}}Err = errors.New({{.Error | Quote}})
+		{{- else if not $sig.Void}}
+		{{.Dependency | Ident}}.{{.Method}}Result = {{.ReturnValue | FormatAs $sig.Returns}}
+		{{- end}}
+		{{- end}}
+		{{- if $s.Expectations.HasReturn}}
+		want := {{$s.Expectations.ReturnValue | FormatValue}}
+		{{- end}}
+
+		{{- if eq $g.Target.Kind "instance"}}
+{{template "sut" $g}}
+		{{- end}}
+		{{- $blanks := ""}}{{$vars := ""}}
+		{{- range $name, $v := $s.Inputs}}{{$blanks = printf "%s, _" $blanks}}{{$vars = printf "%s, %s" $vars (VarName $name)}}{{end}}
+		{{- range $.Dependencies}}{{$blanks = printf "%s, _" $blanks}}{{$vars = printf "%s, %s" $vars (Ident .FieldName)}}{{end}}
+		{{- if $s.Expectations.HasReturn}}{{$blanks = printf "%s, _" $blanks}}{{$vars = printf "%s, want" $vars}}{{end}}
+		{{- if $vars}}
+		{{slice $blanks 2}} = {{slice $vars 2}} // Used by the commented Act and Assert steps
 		{{- end}}
 
 		// Act
-		// result := sut.{{$.Target.MethodName}}()
+		// {{if $s.Expectations.Error}}_, err{{else}}result{{end}} := {{$call}}
 
 		// Assert
-		{{- if $s.Expectations.ReturnValue}}
-		// assert.Equal(t, {{$s.Expectations.ReturnValue | FormatValue}}, result)
+		{{- if $s.Expectations.Error}}
+		// if err == nil {
+		// 	t.Fatal({{printf "expected %s" $s.Expectations.Error | Quote}})
+		// }
+		{{- else if $s.Expectations.HasReturn}}
+		// if result != want {
+		// 	t.Errorf("got %v, want %v", result, want)
+		// }
 		{{- end}}
 	})
 	{{- end}}
-}`
+}
+{{- end}}{{template "builders" .}}`
+
+// Retorno de um método de dependência; com "error" quando algum mock falha
+const goResultsTmpl = `
+{{- if and (not .Void) .Fails}} ({{Type .Returns}}, error)
+{{- else if not .Void}} {{Type .Returns}}
+{{- else if .Fails}} error
+{{- end}}`
+
+// Argumentos de New<Classe>: dependências do construtor e functional options
+const goArgsTmpl = `
+{{- range $i, $e := .InjectedBy "constructor" "options"}}
+{{- if $i}}, {{end}}
+{{- if eq $e.Injection "options"}}With{{$e.FieldName | ToPascal}}({{$e.FieldName | Ident}})
+{{- else}}{{$e.FieldName | Ident}}{{end}}
+{{- end}}`
+
+// Construção do SUT num cenário, conforme a injeção do target
+const goSutTmpl = `
+{{- if eq .Target.Injection "container"}}
+		// var sut *{{.Target.ClassName}}
+		// c := dig.New()
+		{{- range .Dependencies}}
+		// _ = c.Provide(func() {{.InterfaceName}} { return {{.FieldName | Ident}} })
+		{{- end}}
+		// _ = c.Provide(New{{.Target.ClassName}})
+		// _ = c.Invoke(func(s *{{.Target.ClassName}}) { sut = s })
+{{- else}}
+		// sut := {{if eq .Target.Injection "factory"}}{{or .Target.Factory (printf "create%s" .Target.ClassName)}}{{else}}New{{.Target.ClassName}}{{end}}({{include "args" .}})
+		{{- range .InjectedBy "property"}}
+		// sut.{{.FieldName}} = {{.FieldName | Ident}}
+		{{- end}}
+{{- end}}`
 
-const csharpTmpl = `using Xunit;
-using NSubstitute;
+// Builders de dados de teste, comuns às três variantes
+const goBuildersTmpl = `
+{{- range $t := .Builders}}
+
+// build{{$t.Name}} returns a {{$t.Name}} with test defaults; opts override single fields.
+func build{{$t.Name}}(opts ...func(*{{$t.Name}})) *{{$t.Name}} {
+	{{$t.Name | ToCamel | Ident}} := &{{$t.Name}}{
+		{{- range $t.Fields}}
+		{{FieldName .Name}}: {{DefaultOf .}},
+		{{- end}}
+	}
+	for _, opt := range opts {
+		opt({{$t.Name | ToCamel | Ident}})
+	}
+	return {{$t.Name | ToCamel | Ident}}
+}
+{{- end}}`
+
+// TEMPLATE C# (xUnit | NUnit | MSTest + NSubstitute | Moq | FakeItEasy)
+const csharpTmpl = `{{- $runner := .Stack.Runner -}}
+{{- $mock := .Stack.Mock -}}
+{{- if eq $runner "nunit"}}using NUnit.Framework;
+{{- else if eq $runner "mstest"}}using Microsoft.VisualStudio.TestTools.UnitTesting;
+{{- else}}using Xunit;
+{{- end}}
+{{if eq $mock "moq"}}using Moq;{{else if eq $mock "fakeiteasy"}}using FakeItEasy;{{else}}using NSubstitute;{{end}}
+{{- if .AnyAsync}}
+using System.Threading.Tasks;
+{{- end}}
 
 namespace Tests
 {
+    {{- if eq $runner "nunit"}}
+    [TestFixture]
+    {{- else if eq $runner "mstest"}}
+    [TestClass]
+    {{- end}}
     public class {{.Target.ClassName}}Tests
     {
         {{- range .Dependencies}}
-        private readonly {{.InterfaceName}} _{{.FieldName}};
+        private {{if eq $runner "xunit"}}readonly {{end}}{{if eq $mock "moq"}}Mock<{{.InterfaceName}}>{{else}}{{.InterfaceName}}{{end}} _{{.FieldName}};
+        {{- end}}
+        {{- if .NeedsSut}}
+        // private {{if eq $runner "xunit"}}readonly {{end}}{{.Target.ClassName}} _sut;
         {{- end}}
-        // private readonly {{.Target.ClassName}} _sut;
     
-        public {{.Target.ClassName}}Tests()
+        {{if eq $runner "nunit"}}[SetUp]
+        public void SetUp()
+        {{- else if eq $runner "mstest"}}[TestInitialize]
+        public void SetUp()
+        {{- else}}public {{.Target.ClassName}}Tests()
+        {{- end}}
         {
             {{- range .Dependencies}}
-            _{{.FieldName}} = Substitute.For<{{.InterfaceName}}>();
+            _{{.FieldName}} = {{if eq $mock "moq"}}new Mock<{{.InterfaceName}}>(){{else if eq $mock "fakeiteasy"}}A.Fake<{{.InterfaceName}}>(){{else}}Substitute.For<{{.InterfaceName}}>(){{end}};
+            {{- end}}
+            {{- if .NeedsSut}}
+            {{- template "sut" .}}
             {{- end}}
-            // _sut = new {{.Target.ClassName}}({{range $i, $e := .Dependencies}}{{if $i}}, {{end}}_{{$e.FieldName}}{{end}});
         }
     
+        {{- range $g := .Groups}}
+        {{- $call := printf "_sut.%s()" (Ident .Target.MethodName)}}
+        {{- if eq .Target.Kind "constructor"}}
+        {{- $call = printf "new %s(%s)" .Target.ClassName (include "args" .)}}
+        {{- else if ne .Target.Kind "instance"}}
+        {{- $call = printf "%s.%s()" .Target.ClassName (Ident .Target.MethodName)}}
+        {{- end}}
+        {{- if .Target.Methods}}
+
+        #region {{.Target.MethodName | ToPascal}}
+        {{- end}}
         {{- if not .Scenarios}}
-        [Fact]
-        public void Should_DoWork()
+        {{if eq $runner "nunit"}}[Test]{{else if eq $runner "mstest"}}[TestMethod]{{else}}[Fact]{{end}}
+        public {{if .Target.Async}}async Task{{else}}void{{end}} {{if .Target.Methods}}{{.Target.MethodName | ToPascal}}_{{end}}Should_DoWork()
         {
             // Arrange
             // Act
-            // var result = _sut.{{.Target.MethodName}}();
+            // var result = {{if .Target.Async}}await {{end}}{{$call}};
             // Assert
         }
         {{- end}}
 
         {{- range .Scenarios}}
-        [Fact(DisplayName = "{{.Description}}")]
-        public void {{.ID | ToPascal}}()
+        {{if eq $runner "nunit"}}[Test(Description = {{.Description | Quote}})]{{else if eq $runner "mstest"}}[TestMethod({{.Description | Quote}})]{{else}}[Fact(DisplayName = {{.Description | Quote}})]{{end}}
+        public {{if $g.Target.Async}}async Task{{else}}void{{end}} {{if $g.Target.Methods}}{{$g.Target.MethodName | ToPascal}}_{{end}}{{.ID | ToPascal | Ident}}()
         {
             // Arrange
+            {{- range $name, $v := .Inputs}}
+            var {{VarName $name}} = {{FormatValue $v}};
+            {{- end}}
             {{- range .MocksSetup}}
-            _{{.Dependency}}.{{.Method}}(Arg.Any<object>()).Returns({{.ReturnValue | FormatValue}});
+            {{- $sig := $.MethodOf .Dependency .Method}}
+            {{- $args := or (and (eq $mock "moq") "It.IsAny<object>()") (and (eq $mock "fakeiteasy") "A<object>._") "Arg.Any<object>()"}}
+            {{- if $sig.Declared}}
+            {{- $args = ""}}
+            {{- range $i, $p := $sig.Params}}
+            {{- if $i}}{{$args = printf "%s, " $args}}{{end}}
+            {{- if eq $mock "moq"}}{{$args = printf "%sIt.IsAny<%s>()" $args (Type $p.Type)}}
+            {{- else if eq $mock "fakeiteasy"}}{{$args = printf "%sA<%s>._" $args (Type $p.Type)}}
+            {{- else}}{{$args = printf "%sArg.Any<%s>()" $args (Type $p.Type)}}{{end}}
+            {{- end}}
+            {{- end}}
+            {{- $exception := printf "new System.Exception(%s)" (Quote .Error)}}
+            {{- $result := printf ".Returns(%s)" (FormatAs $sig.Returns .ReturnValue)}}
+            {{- if and .Error $sig.Async $sig.Void (ne $mock "moq") (ne $mock "fakeiteasy")}}
+            {{- $result = printf ".Returns(Task.FromException(%s))" $exception}}
+            {{- else if and .Error $sig.Async (ne $mock "moq") (ne $mock "fakeiteasy")}}
+            {{- $result = printf ".Returns(Task.FromException<%s>(%s))" (Type $sig.Returns) $exception}}
+            {{- else if and .Error $sig.Async}}
+            {{- $result = printf ".ThrowsAsync(%s)" $exception}}
+            {{- else if and .Error (ne $mock "moq") (ne $mock "fakeiteasy")}}
+            {{- $result = printf ".Returns(_ => throw %s)" $exception}}
+            {{- else if .Error}}
+            {{- $result = printf ".Throws(%s)" $exception}}
+            {{- else if and $sig.Async $sig.Void}}
+            {{- $result = ".Returns(Task.CompletedTask)"}}
+            {{- else if $sig.Async}}
+            {{- $result = printf ".Returns(Task.FromResult<%s>(%s))" (Type $sig.Returns) (FormatAs $sig.Returns .ReturnValue)}}
+            {{- else if $sig.Void}}
+            {{- $result = or (and (eq $mock "fakeiteasy") ".DoesNothing()") ""}}
             {{- end}}
+            {{- if eq $mock "moq"}}
+            _{{.Dependency}}.Setup(x => x.{{.Method | Ident}}({{$args}})){{$result}};
+            {{- else if eq $mock "fakeiteasy"}}
+            A.CallTo(() => _{{.Dependency}}.{{.Method | Ident}}({{$args}})){{$result}};
+            {{- else if and $sig.Void (not $sig.Async)}}
+            _{{.Dependency}}.When(x => x.{{.Method | Ident}}({{$args}})).Do(_ => {{if .Error}}throw {{$exception}}{{else}}{ }{{end}});
+            {{- else}}
+            _{{.Dependency}}.{{.Method | Ident}}({{$args}}){{$result}};
+            {{- end}}
+            {{- end}}
+            {{- if .Expectations.Error}}
+    
+            // Act & Assert
+            {{- if eq $runner "nunit"}}
+            // Assert.{{if $g.Target.Async}}ThrowsAsync{{else}}Throws{{end}}<{{.Expectations.Error}}>(() => {{$call}});
+            {{- else if eq $runner "mstest"}}
+            // {{if $g.Target.Async}}await Assert.ThrowsExceptionAsync{{else}}Assert.ThrowsException{{end}}<{{.Expectations.Error}}>(() => {{$call}});
+            {{- else}}
+            // {{if $g.Target.Async}}await Assert.ThrowsAsync{{else}}Assert.Throws{{end}}<{{.Expectations.Error}}>(() => {{$call}});
+            {{- end}}
+            {{- else}}
     
             // Act
-            // var result = _sut.{{$.Target.MethodName}}();
+            // var result = {{if $g.Target.Async}}await {{end}}{{$call}};
     
             // Assert
-            {{- if .Expectations.ReturnValue}}
+            {{- if .Expectations.HasReturn}}
+            {{- if eq $runner "nunit"}}
+            // Assert.That(result, Is.EqualTo({{.Expectations.ReturnValue | FormatValue}}));
+            {{- else if eq $runner "mstest"}}
+            // Assert.AreEqual({{.Expectations.ReturnValue | FormatValue}}, result);
+            {{- else}}
             // Assert.Equal({{.Expectations.ReturnValue | FormatValue}}, result);
             {{- end}}
+            {{- end}}
+            {{- end}}
+        }
+        {{- end}}
+        {{- if .Target.Methods}}
+        #endregion
+        {{- end}}
+        {{- end}}
+    }
+    {{- range $t := .Builders}}
+
+    internal class {{$t.Name}}Builder
+    {
+        {{- range $t.Fields}}
+        private {{Type .Type}} _{{.Name | ToCamel}} = {{DefaultOf .}};
+        {{- end}}
+        {{- range $t.Fields}}
+
+        public {{$t.Name}}Builder With{{FieldName .Name}}({{Type .Type}} {{.Name | ToCamel | Ident}})
+        {
+            _{{.Name | ToCamel}} = {{.Name | ToCamel | Ident}};
+            return this;
         }
         {{- end}}
+
+        public {{$t.Name}} Build() => new {{$t.Name}}
+        {
+            {{- range $t.Fields}}
+            {{FieldName .Name}} = _{{.Name | ToCamel}},
+            {{- end}}
+        };
     }
-}`
+    {{- end}}
+}
+{{- define "args"}}
+{{- range $i, $e := .InjectedBy "constructor" "options"}}{{if $i}}, {{end}}_{{$e.FieldName}}{{if eq $.Stack.Mock "moq"}}.Object{{end}}{{end}}
+{{- end}}
+{{- define "sut"}}
+{{- $object := or (and (eq .Stack.Mock "moq") ".Object") ""}}
+{{- if eq .Target.Injection "container"}}
+            // var services = new ServiceCollection();
+            {{- range .Dependencies}}
+            // services.AddSingleton<{{.InterfaceName}}>(_{{.FieldName}}{{$object}});
+            {{- end}}
+            // services.AddTransient<{{.Target.ClassName}}>();
+            // _sut = services.BuildServiceProvider().GetRequiredService<{{.Target.ClassName}}>();
+{{- else}}
+            // _sut = {{if eq .Target.Injection "factory"}}{{or .Target.Factory (printf "%s.Create" .Target.ClassName)}}{{else}}new {{.Target.ClassName}}{{end}}({{include "args" .}});
+            {{- range .InjectedBy "property"}}
+            // _sut.{{.FieldName | ToPascal}} = _{{.FieldName}}{{$object}};
+            {{- end}}
+{{- end}}
+{{- end}}`
 
+// TEMPLATE NODE (node:test)
 const nodeNativeTmpl = `import { describe, it, mock } from 'node:test';
 import assert from 'node:assert';
 // import { {{.Target.ClassName}} } from '../src/{{.Target.ClassName}}.js'; 
 
-describe('{{.Target.ClassName}}', () => {
+describe({{.Target.ClassName | Quote}}, () => {
     
+    {{- range $g := .Groups}}
+    {{- if .Target.Methods}}
+
+    describe({{.Target.MethodName | Quote}}, () => { {{- include "node:test tests" . | Indent 4}}
+    });
+    {{- else}}
+    {{- template "node:test tests" .}}
+    {{- end}}
+    {{- end}}
+});
+{{- template "builders" .}}`
+
+// Testes do node:test, um bloco por método do target (ver MetaFramework.Groups)
+const nodeNativeTestsTmpl = `
+    {{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+    {{- if eq .Target.Kind "constructor"}}
+    {{- $call = printf "new %s(%s)" .Target.ClassName (include "args" .)}}
+    {{- else if eq .Target.Kind "static"}}{{$call = printf "%s.%s()" .Target.ClassName (Ident .Target.MethodName)}}
+    {{- else if eq .Target.Kind "function"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}
+    {{- end}}
     {{- if not .Scenarios}}
-    it('should execute correctly', () => {
+    it('should execute correctly', {{if .Target.Async}}async {{end}}() => {
+        {{- if eq .Target.Kind "instance"}}
         // const sut = new {{.Target.ClassName}}();
-        // const result = sut.{{.Target.MethodName}}();
+        {{- end}}
+        // const result = {{if .Target.Async}}await {{end}}{{$call}};
         // assert.ok(result);
     });
     {{- end}}
 
     {{- range $s := .Scenarios}}
-    it('{{$s.Description}}', () => {
+    it({{$s.Description | Quote}}, {{if $.Target.Async}}async {{end}}() => {
         // Arrange
+        {{- range $name, $v := $s.Inputs}}
+        const {{VarName $name}} = {{FormatValue $v}};
+        {{- end}}
         {{- range $dep := $.Dependencies}}
-        const {{$dep.FieldName}} = {
+        const {{$dep.FieldName | Ident}} = {
              {{- range $m := $s.MocksSetup}}
                 {{- if eq $m.Dependency $dep.FieldName}}
-                {{$m.Method}}: mock.fn(),
+                {{$m.Method | Ident}}: mock.fn(),
                 {{- end}}
              {{- end}}
         };
         {{- end}}
 
         {{- range $s.MocksSetup}}
-        // Mock Return
-        {{.Dependency}}.{{.Method}}.mock.mockImplementation(() => {{.ReturnValue | FormatValue}});
+        {{- $sig := $.MethodOf .Dependency .Method}}
+        // Configure Mock Return
+        {{.Dependency | Ident}}.{{.Method | Ident}}.mock.mockImplementation({{if $sig.Async}}async {{end}}() => {{if .Error}}{ throw new Error({{.Error | Quote}}); }{{else}}({{.ReturnValue | FormatAs $sig.Returns}}){{end}});
         {{- end}}
+        {{- if eq $.Target.Kind "instance"}}
 
         // Init SUT
-        // const sut = new {{$.Target.ClassName}}({{range $i, $e := $.Dependencies}}{{if $i}}, {{end}}{{$e.FieldName}}{{end}});
+        {{- template "sut" $}}
+        {{- end}}
+        {{- if $s.Expectations.Error}}
+
+        // Act & Assert
+        // {{if $.Target.Async}}await assert.rejects({{else}}assert.throws({{end}}() => {{$call}}, {{$s.Expectations.Error}});
+        {{- else}}
 
         // Act
-        // const result = sut.{{$.Target.MethodName}}();
+        // const result = {{if $.Target.Async}}await {{end}}{{$call}};
 
         // Assert
-        {{- if $s.Expectations.ReturnValue}}
+        {{- if $s.Expectations.HasReturn}}
         // assert.strictEqual(result, {{$s.Expectations.ReturnValue | FormatValue}});
         {{- end}}
+        {{- end}}
     });
-    {{- end}}
-});`
+    {{- end}}`
 
 // TEMPLATE KOTLIN (MockK + JUnit5)
-const kotlinTmpl = `import io.mockk.every
+const kotlinTmpl = `{{if .AnyAsync}}import io.mockk.coEvery
+{{end}}import io.mockk.every
 import io.mockk.mockk
+{{- if .Target.Async}}
+import kotlinx.coroutines.test.runTest
+{{- end}}
+{{- if .Target.Methods}}
+import org.junit.jupiter.api.Nested
+{{- end}}
 import org.junit.jupiter.api.Test
 import org.junit.jupiter.api.Assertions.assertEquals
+{{- if .ExpectsError}}
+import org.junit.jupiter.api.assertThrows
+{{- end}}
 
 class {{.Target.ClassName}}Test {
     {{- range .Dependencies}}
-    private val {{.FieldName}}: {{.InterfaceName}} = mockk()
+    private val {{.FieldName | Ident}}: {{.InterfaceName}} = mockk()
+    {{- end}}
+    {{- if .NeedsSut}}
+    {{- template "sut" .}}
     {{- end}}
-    // private val sut = {{.Target.ClassName}}({{range $i, $e := .Dependencies}}{{if $i}}, {{end}}{{$e.FieldName}}{{end}})
 
+    {{- range $g := .Groups}}
+    {{- if .Target.Methods}}
+
+    @Nested
+    inner class {{.Target.MethodName | ToPascal}} { {{- include "tests" . | Indent 4}}
+    }
+    {{- else}}
+    {{- template "tests" .}}
+    {{- end}}
+    {{- end}}
+    {{- range $t := .Builders}}
+
+    private fun build{{$t.Name}}(
+        {{- range $t.Fields}}
+        {{FieldName .Name}}: {{Type .Type}} = {{DefaultOf .}},
+        {{- end}}
+    ) = {{$t.Name}}({{range $i, $f := $t.Fields}}{{if $i}}, {{end}}{{FieldName $f.Name}} = {{FieldName $f.Name}}{{end}})
+    {{- end}}
+}
+{{- define "args"}}
+{{- range $i, $e := .InjectedBy "constructor" "options"}}{{if $i}}, {{end}}{{$e.FieldName | Ident}}{{end}}
+{{- end}}
+{{- define "sut"}}
+{{- if eq .Target.Injection "container"}}
+    // Koin: make the class implement KoinTest
+    // @JvmField @RegisterExtension
+    // val koin = KoinTestExtension.create { modules(module { {{range .Dependencies}}single { {{.FieldName | Ident}} }; {{end}}singleOf(::{{.Target.ClassName}}) }) }
+    // private val sut: {{.Target.ClassName}} by inject()
+{{- else}}
+    {{- $props := ""}}
+    {{- range $i, $e := .InjectedBy "property"}}{{$props = printf "%s%sit.%s = %s" $props (or (and $i "; ") "") (Ident $e.FieldName) (Ident $e.FieldName)}}{{end}}
+    // private val sut = {{if eq .Target.Injection "factory"}}{{or .Target.Factory (printf "%s.create" .Target.ClassName)}}{{else}}{{.Target.ClassName}}{{end}}({{include "args" .}}){{if $props}}.also { {{$props}} }{{end}}
+{{- end}}
+{{- end}}
+{{- define "tests"}}
+{{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+{{- if eq .Target.Kind "constructor"}}
+{{- $call = printf "%s(%s)" .Target.ClassName (include "args" .)}}
+{{- else if eq .Target.Kind "static"}}{{$call = printf "%s.%s()" .Target.ClassName (Ident .Target.MethodName)}}
+{{- else if eq .Target.Kind "function"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}
+{{- end}}
     {{- if not .Scenarios}}
     @Test
-    fun ` + "`should execute correctly`" + `() {
-        // val result = sut.{{.Target.MethodName}}()
+    fun ` + "`should execute correctly`" + `(){{if .Target.Async}} = runTest{{end}} {
+        // val result = {{$call}}
         // assertEquals(expected, result)
     }
     {{- end}}
 
     {{- range $s := .Scenarios}}
     @Test
-    fun ` + "`{{$s.Description}}`" + `() {
+    fun ` + "`{{$s.Description | BacktickName}}`" + `(){{if $.Target.Async}} = runTest{{end}} {
         // Arrange
+        {{- range $name, $v := $s.Inputs}}
+        val {{VarName $name}} = {{FormatValue $v}}
+        {{- end}}
         {{- range $m := $s.MocksSetup}}
-        every { {{.Dependency}}.{{.Method}}(any()) } returns {{.ReturnValue | FormatValue}}
+        {{- $sig := $.MethodOf .Dependency .Method}}
+        {{- if $sig.Declared}}
+        {{if $sig.Async}}coEvery{{else}}every{{end}} { {{.Dependency | Ident}}.{{.Method | Ident}}({{range $i, $p := $sig.Params}}{{if $i}}, {{end}}any(){{end}}) } {{if .Error}}throws Exception({{.Error | Quote}}){{else}}returns {{if $sig.Void}}Unit{{else}}{{.ReturnValue | FormatAs $sig.Returns}}{{end}}{{end}}
+        {{- else}}
+        every { {{.Dependency | Ident}}.{{.Method | Ident}}(any()) } {{if .Error}}throws Exception({{.Error | Quote}}){{else}}returns {{.ReturnValue | FormatAs $sig.Returns}}{{end}}
+        {{- end}}
         {{- end}}
+        {{- if $s.Expectations.Error}}
+
+        // Act & Assert
+        // assertThrows<{{$s.Expectations.Error}}> { {{$call}} }
+        {{- else}}
 
         // Act
-        // val result = sut.{{$.Target.MethodName}}()
+        // val result = {{$call}}
 
         // Assert
-        {{- if $s.Expectations.ReturnValue}}
+        {{- if $s.Expectations.HasReturn}}
         // assertEquals({{$s.Expectations.ReturnValue | FormatValue}}, result)
         {{- end}}
+        {{- end}}
     }
     {{- end}}
-}`
-
-// TEMPLATE JAVA (Mockito + JUnit5)
-const javaTmpl = `import org.junit.jupiter.api.Test;
-import org.junit.jupiter.api.extension.ExtendWith;
+{{- end}}`
+
+// TEMPLATE JAVA (Mockito + JUnit 5 | JUnit 4 | TestNG)
+const javaTmpl = `{{- $runner := .Stack.Runner -}}
+{{- /* Container: Spring monta o SUT; factory ou injeção mista: setUp manual; senão @InjectMocks */ -}}
+{{- $spring := and .NeedsSut (eq .Target.Injection "container") -}}
+{{- $manual := and .NeedsSut (not $spring) (or (eq .Target.Injection "factory") (and (.InjectedBy "property") (.InjectedBy "constructor" "options"))) -}}
+{{- $injectMocks := and .NeedsSut (not $spring) (not $manual) -}}
+{{- if .Uses "list"}}import java.util.Arrays;
+import java.util.List;
+{{end}}{{if .Uses "object"}}import java.util.HashMap;
+import java.util.Map;
+{{end}}{{if eq $runner "junit4" -}}
+import org.junit.Test;
+{{if $manual}}import org.junit.Before;
+{{end}}import org.junit.runner.RunWith;
+{{if $spring}}import org.springframework.beans.factory.annotation.Autowired;
+import org.springframework.boot.test.mock.mockito.MockBean;
+import org.springframework.test.context.ContextConfiguration;
+import org.springframework.test.context.junit4.SpringRunner;
+{{else}}import org.mockito.Mock;
+{{if $injectMocks}}import org.mockito.InjectMocks;
+{{end}}import org.mockito.junit.MockitoJUnitRunner;
+{{end}}import static org.mockito.Mockito.when;
+import static org.mockito.ArgumentMatchers.*;
+import static org.junit.Assert.assertEquals;
+{{if .ExpectsError}}import static org.junit.Assert.assertThrows;
+{{end}}
+{{if $spring}}@RunWith(SpringRunner.class)
+@ContextConfiguration(classes = {{.Target.ClassName}}.class)
+{{- else}}@RunWith(MockitoJUnitRunner.class){{end}}
+public class {{.Target.ClassName}}Test {
+{{- else if eq $runner "testng" -}}
+import org.testng.annotations.BeforeMethod;
+import org.testng.annotations.Test;
+{{if $spring}}import org.springframework.beans.factory.annotation.Autowired;
+import org.springframework.boot.test.mock.mockito.MockBean;
+import org.springframework.test.context.ContextConfiguration;
+import org.springframework.test.context.testng.AbstractTestNGSpringContextTests;
+{{else}}import org.mockito.Mock;
+{{if $injectMocks}}import org.mockito.InjectMocks;
+{{end}}import org.mockito.MockitoAnnotations;
+{{end}}import static org.mockito.Mockito.when;
+import static org.mockito.ArgumentMatchers.*;
+import static org.testng.Assert.assertEquals;
+{{if .ExpectsError}}import static org.testng.Assert.assertThrows;
+{{end}}
+{{if $spring}}@ContextConfiguration(classes = {{.Target.ClassName}}.class)
+{{end}}public class {{.Target.ClassName}}Test{{if $spring}} extends AbstractTestNGSpringContextTests{{end}} {
+{{- else -}}
+{{if .Target.Methods}}import org.junit.jupiter.api.Nested;
+{{end}}{{if $manual}}import org.junit.jupiter.api.BeforeEach;
+{{end}}import org.junit.jupiter.api.Test;
+{{if $spring}}import org.springframework.beans.factory.annotation.Autowired;
+import org.springframework.boot.test.mock.mockito.MockBean;
+import org.springframework.test.context.junit.jupiter.SpringJUnitConfig;
+{{else}}import org.junit.jupiter.api.extension.ExtendWith;
 import org.mockito.Mock;
-import org.mockito.InjectMocks;
-import org.mockito.junit.jupiter.MockitoExtension;
-import static org.mockito.Mockito.when;
-import static org.mockito.ArgumentMatchers.any;
+{{if $injectMocks}}import org.mockito.InjectMocks;
+{{end}}import org.mockito.junit.jupiter.MockitoExtension;
+{{end}}import static org.mockito.Mockito.when;
+import static org.mockito.ArgumentMatchers.*;
 import static org.junit.jupiter.api.Assertions.assertEquals;
-
-@ExtendWith(MockitoExtension.class)
+{{if .ExpectsError}}import static org.junit.jupiter.api.Assertions.assertThrows;
+{{end}}
+{{if $spring}}@SpringJUnitConfig({{.Target.ClassName}}.class){{else}}@ExtendWith(MockitoExtension.class){{end}}
 class {{.Target.ClassName}}Test {
+{{- end}}
+{{- $visibility := ""}}{{if ne $runner "junit5"}}{{$visibility = "public "}}{{end}}
 
     {{- range .Dependencies}}
-    @Mock
-    {{.InterfaceName}} {{.FieldName}};
+    @{{if $spring}}MockBean{{else}}Mock{{end}}
+    {{.InterfaceName}} {{.FieldName | Ident}};
     {{- end}}
-
+    {{- if .NeedsSut}}
+{{if $spring}}
+    @Autowired
+{{- else if $injectMocks}}
     @InjectMocks
+{{- end}}
     {{.Target.ClassName}} sut;
+    {{- end}}
+
+    {{- if or (and (eq $runner "testng") (not $spring)) $manual}}
+
+    {{if eq $runner "testng"}}@BeforeMethod{{else if eq $runner "junit4"}}@Before{{else}}@BeforeEach{{end}}
+    {{$visibility}}void setUp() {
+        {{- if eq $runner "testng"}}
+        MockitoAnnotations.openMocks(this);
+        {{- end}}
+        {{- if $manual}}
+        sut = {{if eq .Target.Injection "factory"}}{{or .Target.Factory (printf "%s.create" .Target.ClassName)}}{{else}}new {{.Target.ClassName}}{{end}}({{include "args" .}});
+        {{- range .InjectedBy "property"}}
+        sut.set{{.FieldName | ToPascal}}({{.FieldName | Ident}});
+        {{- end}}
+        {{- end}}
+    }
+    {{- end}}
 
+    {{- range $g := .Groups}}
+    {{- if and .Target.Methods (eq $runner "junit5")}}
+
+    @Nested
+    class {{.Target.MethodName | ToPascal}} { {{- include "tests" . | Indent 4}}
+    }
+    {{- else}}
+    {{- template "tests" .}}
+    {{- end}}
+    {{- end}}
+}
+{{- range $t := .Builders}}
+
+class {{$t.Name}}Builder {
+    {{- range $t.Fields}}
+    private {{Type .Type}} {{FieldName .Name}} = {{DefaultOf .}};
+    {{- end}}
+    {{- range $t.Fields}}
+
+    {{$t.Name}}Builder with{{.Name | ToPascal}}({{Type .Type}} {{FieldName .Name}}) {
+        this.{{FieldName .Name}} = {{FieldName .Name}};
+        return this;
+    }
+    {{- end}}
+
+    {{$t.Name}} build() {
+        return new {{$t.Name}}({{range $i, $f := $t.Fields}}{{if $i}}, {{end}}{{FieldName $f.Name}}{{end}});
+    }
+}
+{{- end}}
+{{- define "args"}}
+{{- range $i, $e := .InjectedBy "constructor" "options"}}{{if $i}}, {{end}}{{$e.FieldName | Ident}}{{end}}
+{{- end}}
+{{- define "tests"}}
+{{- $runner := .Stack.Runner}}
+{{- $visibility := ""}}{{if ne $runner "junit5"}}{{$visibility = "public "}}{{end}}
+{{- $prefix := ""}}{{if and .Target.Methods (ne $runner "junit5")}}{{$prefix = printf "%s_" (ToCamel .Target.MethodName)}}{{end}}
+{{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+{{- if eq .Target.Kind "constructor"}}
+{{- $call = printf "new %s(%s)" .Target.ClassName (include "args" .)}}
+{{- else if ne .Target.Kind "instance"}}{{$call = printf "%s.%s()" .Target.ClassName (Ident .Target.MethodName)}}
+{{- end}}
     {{- if not .Scenarios}}
     @Test
-    void shouldExecuteCorrectly() {
-        // var result = sut.{{.Target.MethodName}}();
+    {{$visibility}}void {{$prefix}}shouldExecuteCorrectly() {
+        // var result = {{$call}}{{if .Target.Async}}.join(){{end}};
         // assertEquals(expected, result);
     }
     {{- end}}
 
     {{- range $s := .Scenarios}}
     @Test
-    void {{$s.ID | ToCamel}}() {
+    {{$visibility}}void {{$prefix}}{{$s.ID | ToCamel | Ident}}() {
         // Arrange
+        {{- range $name, $v := $s.Inputs}}
+        var {{VarName $name}} = {{FormatValue $v}};
+        {{- end}}
         {{- range $m := $s.MocksSetup}}
-        when({{.Dependency}}.{{.Method}}(any())).thenReturn({{.ReturnValue | FormatValue}});
+        {{- $sig := $.MethodOf .Dependency .Method}}
+        {{- $exception := printf "new RuntimeException(%s)" (Quote .Error)}}
+        {{- if not $sig.Declared}}
+        when({{.Dependency | Ident}}.{{.Method | Ident}}(any())).{{if .Error}}thenThrow({{$exception}}){{else}}thenReturn({{.ReturnValue | FormatAs $sig.Returns}}){{end}};
+        {{- else if $sig.Async}}
+        when({{.Dependency | Ident}}.{{.Method | Ident}}({{range $i, $p := $sig.Params}}{{if $i}}, {{end}}{{JavaMatcher $p.Type}}{{end}})).thenReturn(java.util.concurrent.CompletableFuture.{{if .Error}}failedFuture({{$exception}}){{else}}completedFuture({{if $sig.Void}}null{{else}}{{.ReturnValue | FormatAs $sig.Returns}}{{end}}){{end}});
+        {{- else if $sig.Void}}
+        org.mockito.Mockito.{{if .Error}}doThrow({{$exception}}){{else}}doNothing(){{end}}.when({{.Dependency | Ident}}).{{.Method | Ident}}({{range $i, $p := $sig.Params}}{{if $i}}, {{end}}{{JavaMatcher $p.Type}}{{end}});
+        {{- else}}
+        when({{.Dependency | Ident}}.{{.Method | Ident}}({{range $i, $p := $sig.Params}}{{if $i}}, {{end}}{{JavaMatcher $p.Type}}{{end}})).{{if .Error}}thenThrow({{$exception}}){{else}}thenReturn({{.ReturnValue | FormatAs $sig.Returns}}){{end}};
+        {{- end}}
         {{- end}}
+        {{- if $s.Expectations.Error}}
+
+        // Act & Assert
+        {{- if $.Target.Async}}
+        // assertThrows(java.util.concurrent.CompletionException.class, () -> {{$call}}.join()); // Cause: {{$s.Expectations.Error | Comment}}
+        {{- else}}
+        // assertThrows({{$s.Expectations.Error}}.class, () -> {{$call}});
+        {{- end}}
+        {{- else}}
 
         // Act
-        // var result = sut.{{$.Target.MethodName}}();
+        // var result = {{$call}}{{if $.Target.Async}}.join(){{end}};
 
         // Assert
-        {{- if $s.Expectations.ReturnValue}}
+        {{- if $s.Expectations.HasReturn}}
+        {{- if eq $runner "testng"}}
+        // assertEquals(result, {{$s.Expectations.ReturnValue | FormatValue}});
+        {{- else}}
         // assertEquals({{$s.Expectations.ReturnValue | FormatValue}}, result);
         {{- end}}
+        {{- end}}
+        {{- end}}
     }
     {{- end}}
-}`
+{{- end}}`
 
 // TEMPLATE PHP (PHPUnit)
 const phpTmpl = `<?php
@@ -295,207 +1154,840 @@ use PHPUnit\Framework\TestCase;
 
 class {{.Target.ClassName}}Test extends TestCase
 {
+    {{- range $g := .Groups}}
+    {{- $call := printf "$sut->%s()" (Ident .Target.MethodName)}}
+    {{- if eq .Target.Kind "constructor"}}
+    {{- $call = printf "new %s(%s)" .Target.ClassName (include "args" .)}}
+    {{- else if eq .Target.Kind "static"}}{{$call = printf "%s::%s()" .Target.ClassName (Ident .Target.MethodName)}}
+    {{- else if eq .Target.Kind "function"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}
+    {{- end}}
     {{- if not .Scenarios}}
-    public function testShouldExecuteCorrectly()
+    public function test{{if .Target.Methods}}{{.Target.MethodName | ToPascal}}{{end}}ShouldExecuteCorrectly()
     {
+        {{- if eq .Target.Kind "instance"}}
         // $sut = new {{.Target.ClassName}}();
+        {{- else}}
+        // $result = {{$call}};
+        {{- end}}
         // $this->assertTrue(true);
     }
     {{- end}}
 
     {{- range $s := .Scenarios}}
-    public function test{{$s.ID | ToPascal}}()
+    public function test{{if $g.Target.Methods}}{{$g.Target.MethodName | ToPascal}}{{end}}{{$s.ID | ToPascal}}()
     {
         // Arrange
+        {{- range $name, $v := $s.Inputs}}
+        {{VarName $name}} = {{FormatValue $v}};
+        {{- end}}
         {{- range $.Dependencies}}
         ${{.FieldName}} = $this->createMock({{.InterfaceName}}::class);
         {{- end}}
         
         {{- range $m := $s.MocksSetup}}
-        ${{.Dependency}}->method('{{.Method}}')->willReturn({{.ReturnValue | FormatValue}});
+        ${{.Dependency}}->method({{.Method | Ident | Quote}}){{if .Error}}->willThrowException(new \Exception({{.Error | Quote}})){{else if not ($.MethodOf .Dependency .Method).Void}}->willReturn({{.ReturnValue | FormatAs ($.MethodOf .Dependency .Method).Returns}}){{end}};
         {{- end}}
 
-        // $sut = new {{$.Target.ClassName}}({{range $i, $e := $.Dependencies}}{{if $i}}, {{end}}${{$e.FieldName}}{{end}});
+        {{- if eq $g.Target.Kind "instance"}}
+{{template "sut" $g}}
+        {{- end}}
+        {{- if $s.Expectations.Error}}
+
+        // Assert & Act
+        // $this->expectException({{$s.Expectations.Error}}::class);
+        // {{$call}};
+        {{- else}}
 
         // Act
-        // $result = $sut->{{$.Target.MethodName}}();
+        // $result = {{$call}};
 
         // Assert
-        {{- if $s.Expectations.ReturnValue}}
+        {{- if $s.Expectations.HasReturn}}
         // $this->assertEquals({{$s.Expectations.ReturnValue | FormatValue}}, $result);
         {{- end}}
+        {{- end}}
     }
     {{- end}}
-}`
+    {{- end}}
+    {{- range $t := .Builders}}
 
-// TEMPLATE TYPESCRIPT (Jest)
-const typeScriptTmpl = `//import { {{.Target.ClassName}} } from './{{.Target.ClassName}}';
-import {describe, beforeEach, it, expect, test } from '@jest/globals';
+    private function build{{$t.Name}}(array $overrides = []): {{$t.Name}}
+    {
+        $fields = array_merge([
+            {{- range $t.Fields}}
+            {{FieldName .Name | Quote}} => {{DefaultOf .}},
+            {{- end}}
+        ], $overrides);
+        return new {{$t.Name}}(...$fields);
+    }
+    {{- end}}
+}
+{{- define "args"}}
+{{- range $i, $e := .InjectedBy "constructor" "options"}}{{if $i}}, {{end}}${{$e.FieldName}}{{end}}
+{{- end}}
+{{- define "sut"}}
+{{- if eq .Target.Injection "container"}}
+        // $container = new \DI\Container();
+        {{- range .Dependencies}}
+        // $container->set({{.InterfaceName}}::class, ${{.FieldName}});
+        {{- end}}
+        // $sut = $container->get({{.Target.ClassName}}::class);
+{{- else}}
+        // $sut = {{if eq .Target.Injection "factory"}}{{or .Target.Factory (printf "%s::create" .Target.ClassName)}}{{else}}new {{.Target.ClassName}}{{end}}({{include "args" .}});
+        {{- range .InjectedBy "property"}}
+        // $sut->set{{.FieldName | ToPascal}}(${{.FieldName}});
+        {{- end}}
+{{- end}}
+{{- end}}`
+
+// TEMPLATE TYPESCRIPT (Jest | Vitest | Mocha + Sinon)
+const typeScriptTmpl = `{{- $runner := .Stack.Runner -}}
+{{- $typed := false}}{{range .Dependencies}}{{if .Methods}}{{$typed = true}}{{end}}{{end -}}
+//import { {{.Target.ClassName}} } from './{{.Target.ClassName}}';
+{{- if eq $runner "vitest"}}
+import { describe, beforeEach, it, expect, vi{{if $typed}}, type Mocked{{end}} } from 'vitest';
+{{- else if eq $runner "mocha"}}
+import { expect } from 'chai';
+import sinon from 'sinon';
+{{- else}}
+import {describe, beforeEach, it, expect, test, jest } from '@jest/globals';
+{{- end}}
 
-describe('{{.Target.ClassName}}', () => {
+describe({{.Target.ClassName | Quote}}, () => {
+    {{- if .NeedsSut}}
     let sut: {{.Target.ClassName}};
+    {{- end}}
     {{- range .Dependencies}}
-    let {{.FieldName}}: any;
+    {{- if not .Methods}}
+    let {{.FieldName | Ident}}: any;
+    {{- else if eq $runner "vitest"}}
+    let {{.FieldName | Ident}}: Mocked<{{.InterfaceName}}>;
+    {{- else if eq $runner "mocha"}}
+    let {{.FieldName | Ident}}: sinon.SinonStubbedInstance<{{.InterfaceName}}>;
+    {{- else}}
+    let {{.FieldName | Ident}}: jest.Mocked<{{.InterfaceName}}>;
+    {{- end}}
     {{- end}}
 
     beforeEach(() => {
-        {{- range .Dependencies}}
-        {{.FieldName}} = {
+        {{- range $dep := .Dependencies}}
+        {{- if not $dep.Methods}}
+        {{$dep.FieldName | Ident}} = {
             // Mock methods here
         };
+        {{- else}}
+        {{$dep.FieldName | Ident}} = {
+            {{- range $m := $.MethodsOf $dep.FieldName}}
+            {{- if not $m.Declared}}
+            {{$m.Name | Ident}}: {{if eq $runner "vitest"}}vi.fn(){{else if eq $runner "mocha"}}sinon.stub(){{else}}jest.fn(){{end}},
+            {{- else if eq $runner "mocha"}}
+            {{$m.Name | Ident}}: sinon.stub<[{{Params $m.Params}}], {{if $m.Async}}Promise<{{Type $m.Returns}}>{{else}}{{Type $m.Returns}}{{end}}>(),
+            {{- else}}
+            {{$m.Name | Ident}}: {{if eq $runner "vitest"}}vi{{else}}jest{{end}}.fn<({{Params $m.Params}}) => {{if $m.Async}}Promise<{{Type $m.Returns}}>{{else}}{{Type $m.Returns}}{{end}}>(),
+            {{- end}}
+            {{- end}}
+        } as {{if eq $runner "vitest"}}Mocked<{{$dep.InterfaceName}}>{{else if eq $runner "mocha"}}sinon.SinonStubbedInstance<{{$dep.InterfaceName}}>{{else}}jest.Mocked<{{$dep.InterfaceName}}>{{end}};
+        {{- end}}
         {{- end}}
-        // sut = new {{.Target.ClassName}}({{range $i, $e := .Dependencies}}{{if $i}}, {{end}}{{.FieldName}}{{end}});
+        {{- if .NeedsSut}}
+        {{- template "sut" .}}
+        {{- end}}
+    });
+    {{- if eq $runner "mocha"}}
+
+    afterEach(() => {
+        sinon.restore();
     });
+    {{- end}}
+
+    {{- range $g := .Groups}}
+    {{- if .Target.Methods}}
+
+    describe({{.Target.MethodName | Quote}}, () => { {{- include "tests" . | Indent 4}}
+    });
+    {{- else}}
+    {{- template "tests" .}}
+    {{- end}}
+    {{- end}}
+});
+{{- range $t := .Builders}}
 
+function build{{$t.Name}}(overrides: Partial<{{$t.Name}}> = {}): {{$t.Name}} {
+    return {
+        {{- range $t.Fields}}
+        {{FieldName .Name}}: {{DefaultOf .}},
+        {{- end}}
+        ...overrides,
+    };
+}
+{{- end}}
+{{- define "args"}}
+{{- range $i, $e := .InjectedBy "constructor" "options"}}{{if $i}}, {{end}}{{$e.FieldName | Ident}}{{end}}
+{{- end}}
+{{- define "sut"}}
+{{- if eq .Target.Injection "container"}}
+        // const container = createContainer();
+        // container.register({ {{range $i, $e := .Dependencies}}{{if $i}}, {{end}}{{$e.FieldName | Ident}}: asValue({{$e.FieldName | Ident}}){{end}} });
+        // sut = container.build({{.Target.ClassName}});
+{{- else}}
+        // sut = {{if eq .Target.Injection "factory"}}{{or .Target.Factory (printf "create%s" .Target.ClassName)}}{{else}}new {{.Target.ClassName}}{{end}}({{include "args" .}});
+        {{- range .InjectedBy "property"}}
+        // sut.{{.FieldName | Ident}} = {{.FieldName | Ident}};
+        {{- end}}
+{{- end}}
+{{- end}}
+{{- define "tests"}}
+{{- $runner := .Stack.Runner}}
+{{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+{{- if eq .Target.Kind "constructor"}}
+{{- $call = printf "new %s(%s)" .Target.ClassName (include "args" .)}}
+{{- else if eq .Target.Kind "static"}}{{$call = printf "%s.%s()" .Target.ClassName (Ident .Target.MethodName)}}
+{{- else if eq .Target.Kind "function"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}
+{{- end}}
     {{- if not .Scenarios}}
-    it('should work', () => {
-        // const result = sut.{{.Target.MethodName}}();
+    it('should work', {{if .Target.Async}}async {{end}}() => {
+        // const result = {{if .Target.Async}}await {{end}}{{$call}};
+        {{- if eq $runner "mocha"}}
+        // expect(result).to.exist;
+        {{- else}}
         // expect(result).toBeDefined();
+        {{- end}}
     });
     {{- end}}
 
     {{- range $s := .Scenarios}}
-    it('{{$s.Description}}', () => {
+    it({{$s.Description | Quote}}, {{if $.Target.Async}}async {{end}}() => {
         // Arrange
+        {{- range $name, $v := $s.Inputs}}
+        const {{VarName $name}} = {{FormatValue $v}};
+        {{- end}}
         {{- range $m := $s.MocksSetup}}
-        {{.Dependency}}.{{.Method}} = jest.fn().mockReturnValue({{.ReturnValue | FormatValue}});
+        {{- $sig := $.MethodOf .Dependency .Method}}
+        {{- $value := FormatAs $sig.Returns .ReturnValue}}{{if $sig.Void}}{{$value = "undefined"}}{{end}}
+        {{- $stub := printf "%s.%s." (Ident .Dependency) (Ident .Method)}}
+        {{- if not ($.DependencyOf .Dependency).Methods}}
+        {{- $stub = printf "%s.%s = %s." (Ident .Dependency) (Ident .Method) (or (and (eq $runner "vitest") "vi.fn()") (and (eq $runner "mocha") "sinon.stub()") "jest.fn()")}}
+        {{- end}}
+        {{- if and .Error $sig.Async}}
+        {{$stub}}{{if eq $runner "mocha"}}rejects{{else}}mockRejectedValue{{end}}(new Error({{.Error | Quote}}));
+        {{- else if .Error}}
+        {{$stub}}{{if eq $runner "mocha"}}throws(new Error({{.Error | Quote}})){{else}}mockImplementation(() => { throw new Error({{.Error | Quote}}); }){{end}};
+        {{- else if $sig.Async}}
+        {{$stub}}{{if eq $runner "mocha"}}resolves{{else}}mockResolvedValue{{end}}({{$value}});
+        {{- else}}
+        {{$stub}}{{if eq $runner "mocha"}}returns{{else}}mockReturnValue{{end}}({{$value}});
+        {{- end}}
+        {{- end}}
+        {{- if $s.Expectations.Error}}
+
+        // Act & Assert
+        {{- if and (eq $runner "mocha") $.Target.Async}}
+        // await expect({{$call}}).to.be.rejectedWith({{$s.Expectations.Error}}); // chai-as-promised
+        {{- else if eq $runner "mocha"}}
+        // expect(() => {{$call}}).to.throw({{$s.Expectations.Error}});
+        {{- else if $.Target.Async}}
+        // await expect({{$call}}).rejects.toThrow({{$s.Expectations.Error}});
+        {{- else}}
+        // expect(() => {{$call}}).toThrow({{$s.Expectations.Error}});
         {{- end}}
+        {{- else}}
 
         // Act
-        // const result = sut.{{$.Target.MethodName}}();
+        // const result = {{if $.Target.Async}}await {{end}}{{$call}};
 
         // Assert
-        {{- if $s.Expectations.ReturnValue}}
+        {{- if $s.Expectations.HasReturn}}
+        {{- if eq $runner "mocha"}}
+        // expect(result).to.equal({{$s.Expectations.ReturnValue | FormatValue}});
+        {{- else}}
         // expect(result).toBe({{$s.Expectations.ReturnValue | FormatValue}});
         {{- end}}
+        {{- end}}
+        {{- end}}
     });
     {{- end}}
-});`
-
-
-const pythonTmpl = `import unittest
-from unittest.mock import MagicMock
-
-class Test{{.Target.ClassName}}(unittest.TestCase):
+{{- end}}`
+
+// TEMPLATE PYTHON (unittest | pytest + pytest-mock)
+const pythonTmpl = `{{if eq .Stack.Runner "pytest"}}{{template "pytest" .}}{{else}}{{template "unittest" .}}{{end}}` +
+	`{{define "unittest"}}` + pythonUnittestTmpl + `{{end}}` +
+	`{{define "pytest"}}` + pythonPytestTmpl + `{{end}}` +
+	`{{define "pytest tests"}}` + pythonPytestTestsTmpl + `{{end}}` +
+	`{{define "builders"}}` + pythonBuildersTmpl + `{{end}}`
+
+const pythonUnittestTmpl = `
+{{- if .Uses "date"}}import datetime
+{{end}}
+{{- if .Uses "uuid"}}import uuid
+{{end -}}
+import unittest
+from unittest.mock import {{if .AnyAsync}}AsyncMock, {{end}}MagicMock
+# from {{.Target.ClassName | ToSnake}} import {{.Target.ClassName}}
+
+class Test{{.Target.ClassName}}(unittest.{{if .Target.Async}}IsolatedAsyncioTestCase{{else}}TestCase{{end}}):
     def setUp(self):
         {{- range .Dependencies}}
         self.mock_{{.FieldName}} = MagicMock()
         {{- end}}
+        {{- if .NeedsSut}}
+        {{- $props := .InjectedBy "property"}}
+        {{- if eq .Target.Injection "container"}}
+        # container = punq.Container()
+        {{- range .Dependencies}}
+        # container.register({{.InterfaceName}}, instance=self.mock_{{.FieldName}})
+        {{- end}}
+        # container.register({{.Target.ClassName}})
+        # self.sut = container.resolve({{.Target.ClassName}})
+        {{- else}}
+        {{- if and (eq .Target.Injection "constructor") (not $props)}}
         # Assumes constructor injection
-        # self.sut = {{.Target.ClassName}}({{range $i, $e := .Dependencies}}{{if $i}}, {{end}}self.mock_{{.FieldName}}{{end}})
+        {{- end}}
+        # self.sut = {{if eq .Target.Injection "factory"}}{{or .Target.Factory (printf "create_%s" (ToSnake .Target.ClassName))}}{{else}}{{.Target.ClassName}}{{end}}({{range $i, $e := .InjectedBy "constructor" "options"}}{{if $i}}, {{end}}self.mock_{{.FieldName}}{{end}})
+        {{- range $props}}
+        # self.sut.{{.FieldName | ToSnake}} = self.mock_{{.FieldName}}
+        {{- end}}
+        {{- end}}
+        {{- end}}
+        {{- if not .Dependencies}}
+        pass
+        {{- end}}
+
+    {{- range $g := .Groups}}
+    {{- $call := printf "self.sut.%s()" (Ident .Target.MethodName)}}
+    {{- if eq .Target.Kind "constructor"}}
+    {{- $call = printf "%s(" .Target.ClassName}}
+    {{- range $i, $e := $.InjectedBy "constructor" "options"}}{{$call = printf "%s%sself.mock_%s" $call (or (and $i ", ") "") $e.FieldName}}{{end}}
+    {{- $call = printf "%s)" $call}}
+    {{- else if eq .Target.Kind "static"}}{{$call = printf "%s.%s()" .Target.ClassName (Ident .Target.MethodName)}}
+    {{- else if eq .Target.Kind "function"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}
+    {{- end}}
+    {{- if not .Scenarios}}
+
+    {{if .Target.Async}}async {{end}}def test_{{if .Target.Methods}}{{.Target.MethodName | ToSnake}}_{{end}}should_execute_correctly(self):
+        # result = {{if .Target.Async}}await {{end}}{{$call}}
+        # self.assertIsNotNone(result)
+        pass
+    {{- end}}
 
     {{- range $s := .Scenarios}}
-    def test_{{$s.ID | ToSnake}}(self):
-        """ {{$s.Description}} """
+
+    {{if $g.Target.Async}}async {{end}}def test_{{if $g.Target.Methods}}{{$g.Target.MethodName | ToSnake}}_{{end}}{{$s.ID | ToSnake}}(self):
+        {{$s.Description | Quote}}
         # Arrange
+        {{- range $name, $v := $s.Inputs}}
+        {{VarName $name}} = {{FormatValue $v}}
+        {{- end}}
         {{- range $m := $s.MocksSetup}}
-        self.mock_{{.Dependency}}.{{.Method}}.ReturnValue = {{.ReturnValue | FormatValue}}
+        {{- $sig := $.MethodOf .Dependency .Method}}
+        {{- if $sig.Async}}
+        self.mock_{{.Dependency}}.{{.Method | Ident}} = AsyncMock({{if .Error}}side_effect=Exception({{.Error | Quote}}){{else}}return_value={{.ReturnValue | FormatAs $sig.Returns}}{{end}})
+        {{- else if .Error}}
+        self.mock_{{.Dependency}}.{{.Method | Ident}}.side_effect = Exception({{.Error | Quote}})
+        {{- else}}
+        self.mock_{{.Dependency}}.{{.Method | Ident}}.return_value = {{.ReturnValue | FormatAs $sig.Returns}}
         {{- end}}
+        {{- end}}
+
+        {{- if $s.Expectations.Error}}
+
+        # Act & Assert
+        # with self.assertRaises({{$s.Expectations.Error}}):
+        #     {{if $g.Target.Async}}await {{end}}{{$call}}
+        {{- else}}
 
         # Act
-        # result = self.sut.{{$.Target.MethodName}}()
+        # result = {{if $g.Target.Async}}await {{end}}{{$call}}
 
         # Assert
-        {{- if $s.Expectations.ReturnValue}}
+        {{- if $s.Expectations.HasReturn}}
         # self.assertEqual(result, {{$s.Expectations.ReturnValue | FormatValue}})
         {{- end}}
-    {{- end}}` 
+        {{- end}}
+    {{- end}}
+    {{- end}}
+{{- template "builders" .}}
+
+
+if __name__ == "__main__":
+    unittest.main()
+`
+
+// No pytest cada dependência vira uma fixture criada com o "mocker" do pytest-mock
+const pythonPytestTmpl = `
+{{- if .Uses "date"}}import datetime
+{{end}}
+{{- if .Uses "uuid"}}import uuid
+{{end -}}
+import pytest
+{{- if .AnyAsync}}
+from unittest.mock import AsyncMock
+{{- end}}
+# from {{.Target.ClassName | ToSnake}} import {{.Target.ClassName}}
+{{- range .Dependencies}}
 
 
-const nodeTmpl = `import { describe, it, mock } from 'node:test';
-import assert from 'node:assert';
-// import { {{.Target.ClassName}} } from '../src/{{.Target.ClassName}}.js'; 
+@pytest.fixture
+def {{.FieldName | Ident}}(mocker):
+    return mocker.MagicMock(name={{.InterfaceName | Quote}})
+{{- end}}
+{{- if .NeedsSut}}
+
+
+@pytest.fixture
+def sut({{range $i, $e := .Dependencies}}{{if $i}}, {{end}}{{$e.FieldName | Ident}}{{end}}):
+    {{- $props := .InjectedBy "property"}}
+    {{- $new := printf "%s(" .Target.ClassName}}
+    {{- if eq .Target.Injection "factory"}}{{$new = printf "%s(" (or .Target.Factory (printf "create_%s" (ToSnake .Target.ClassName)))}}{{end}}
+    {{- range $i, $e := .InjectedBy "constructor" "options"}}{{$new = printf "%s%s%s" $new (or (and $i ", ") "") (Ident $e.FieldName)}}{{end}}
+    {{- $new = printf "%s)" $new}}
+    {{- if eq .Target.Injection "container"}}
+    # container = punq.Container()
+    {{- range .Dependencies}}
+    # container.register({{.InterfaceName}}, instance={{.FieldName | Ident}})
+    {{- end}}
+    # container.register({{.Target.ClassName}})
+    # return container.resolve({{.Target.ClassName}})
+    {{- else if $props}}
+    # sut = {{$new}}
+    {{- range $props}}
+    # sut.{{.FieldName | ToSnake}} = {{.FieldName | Ident}}
+    {{- end}}
+    # return sut
+    {{- else}}
+    {{- if eq .Target.Injection "constructor"}}
+    # Assumes constructor injection
+    {{- end}}
+    # return {{$new}}
+    {{- end}}
+    return None
+{{- end}}
+
+{{- range $g := .Groups}}
+{{- if .Target.Methods}}
+
+
+class Test{{.Target.MethodName | ToPascal}}: {{- include "pytest tests" . | Indent 4}}
+{{- else}}
+{{- template "pytest tests" .}}
+{{- end}}
+{{- end}}
+{{- template "builders" .}}
+`
+
+// Testes do pytest; com vários métodos no target, cada um vira uma classe Test<Método>
+const pythonPytestTestsTmpl = `
+{{- $self := ""}}{{if .Target.Methods}}{{$self = "self, "}}{{end}}
+{{- $sut := "sut"}}
+{{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+{{- if eq .Target.Kind "constructor"}}
+{{- $call = printf "%s(" .Target.ClassName}}
+{{- range $i, $e := .InjectedBy "constructor" "options"}}{{$call = printf "%s%s%s" $call (or (and $i ", ") "") (Ident $e.FieldName)}}{{end}}
+{{- $call = printf "%s)" $call}}
+{{- else if eq .Target.Kind "static"}}{{$call = printf "%s.%s()" .Target.ClassName (Ident .Target.MethodName)}}
+{{- else if eq .Target.Kind "function"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}
+{{- end}}
+{{- if ne .Target.Kind "instance"}}{{$self = or (and .Target.Methods "self") ""}}{{$sut = ""}}{{end}}
+{{- if not .Scenarios}}
+{{- if not .Target.Methods}}
+
+{{end}}
+{{if .Target.Async}}@pytest.mark.asyncio
+async {{end}}def test_should_execute_correctly({{$self}}{{$sut}}):
+    # result = {{if .Target.Async}}await {{end}}{{$call}}
+    # assert result is not None
+    pass
+{{- end}}
+
+{{- range $i, $s := .Scenarios}}
+{{- if not $.Target.Methods}}
+
+{{else if $i}}
+{{end}}
+{{if $.Target.Async}}@pytest.mark.asyncio
+async {{end}}def test_{{$s.ID | ToSnake}}({{$self}}{{$sut}}{{range $i, $e := $.Dependencies}}{{if or $sut $self $i}}, {{end}}{{$e.FieldName | Ident}}{{end}}):
+    {{$s.Description | Quote}}
+    # Arrange
+    {{- range $name, $v := $s.Inputs}}
+    {{VarName $name}} = {{FormatValue $v}}
+    {{- end}}
+    {{- range $m := $s.MocksSetup}}
+    {{- $sig := $.MethodOf .Dependency .Method}}
+    {{- if $sig.Async}}
+    {{.Dependency | Ident}}.{{.Method | Ident}} = AsyncMock({{if .Error}}side_effect=Exception({{.Error | Quote}}){{else}}return_value={{.ReturnValue | FormatAs $sig.Returns}}{{end}})
+    {{- else if .Error}}
+    {{.Dependency | Ident}}.{{.Method | Ident}}.side_effect = Exception({{.Error | Quote}})
+    {{- else}}
+    {{.Dependency | Ident}}.{{.Method | Ident}}.return_value = {{.ReturnValue | FormatAs $sig.Returns}}
+    {{- end}}
+    {{- end}}
+
+    {{- if $s.Expectations.Error}}
+
+    # Act & Assert
+    # with pytest.raises({{$s.Expectations.Error}}):
+    #     {{if $.Target.Async}}await {{end}}{{$call}}
+    {{- else}}
+
+    # Act
+    # result = {{if $.Target.Async}}await {{end}}{{$call}}
+
+    # Assert
+    {{- if $s.Expectations.HasReturn}}
+    # assert result == {{$s.Expectations.ReturnValue | FormatValue}}
+    {{- end}}
+    {{- end}}
+{{- end}}`
+
+// Builders de dados de teste, comuns ao unittest e ao pytest
+const pythonBuildersTmpl = `
+{{- range $t := .Builders}}
+
+
+def build_{{$t.Name | ToSnake}}(**overrides):
+    fields = {
+        {{- range $t.Fields}}
+        {{FieldName .Name | Quote}}: {{DefaultOf .}},
+        {{- end}}
+    }
+    fields.update(overrides)
+    return {{$t.Name}}(**fields)
+{{- end}}`
+
+// TEMPLATE NODE (node:test | Jest)
+// Cada runner é um bloco nomeado; o template da linguagem só escolhe qual executar.
+const nodeTmpl = `{{if eq .Stack.Runner "jest"}}{{template "jest" .}}{{else}}{{template "node:test" .}}{{end}}` +
+	`{{define "node:test"}}` + nodeNativeTmpl + `{{end}}` +
+	`{{define "jest"}}` + nodeJestTmpl + `{{end}}` +
+	`{{define "node:test tests"}}` + nodeNativeTestsTmpl + `{{end}}` +
+	`{{define "jest tests"}}` + nodeJestTestsTmpl + `{{end}}` +
+	`{{define "builders"}}` + nodeBuildersTmpl + `{{end}}` +
+	`{{define "args"}}` + nodeArgsTmpl + `{{end}}` +
+	`{{define "sut"}}` + nodeSutTmpl + `{{end}}`
+
+// Argumentos do construtor (ou da fábrica) do SUT
+const nodeArgsTmpl = `
+{{- range $i, $e := .InjectedBy "constructor" "options"}}{{if $i}}, {{end}}{{$e.FieldName | Ident}}{{end}}`
+
+// Construção do SUT num teste, conforme a injeção do target (container via awilix)
+const nodeSutTmpl = `
+{{- if eq .Target.Injection "container"}}
+        // const container = createContainer();
+        // container.register({ {{range $i, $e := .Dependencies}}{{if $i}}, {{end}}{{$e.FieldName | Ident}}: asValue({{$e.FieldName | Ident}}){{end}} });
+        // const sut = container.build({{.Target.ClassName}});
+{{- else}}
+        // const sut = {{if eq .Target.Injection "factory"}}{{or .Target.Factory (printf "create%s" .Target.ClassName)}}{{else}}new {{.Target.ClassName}}{{end}}({{include "args" .}});
+        {{- range .InjectedBy "property"}}
+        // sut.{{.FieldName | Ident}} = {{.FieldName | Ident}};
+        {{- end}}
+{{- end}}`
+
+const nodeJestTmpl = `import { describe, it, expect, jest } from '@jest/globals';
+// import { {{.Target.ClassName}} } from '../src/{{.Target.ClassName}}.js';
+
+describe({{.Target.ClassName | Quote}}, () => {
+
+    {{- range $g := .Groups}}
+    {{- if .Target.Methods}}
+
+    describe({{.Target.MethodName | Quote}}, () => { {{- include "jest tests" . | Indent 4}}
+    });
+    {{- else}}
+    {{- template "jest tests" .}}
+    {{- end}}
+    {{- end}}
+});
+{{- template "builders" .}}`
+
+// Testes do Jest, um bloco por método do target (ver MetaFramework.Groups)
+const nodeJestTestsTmpl = `
+    {{- $call := printf "sut.%s()" (Ident .Target.MethodName)}}
+    {{- if eq .Target.Kind "constructor"}}
+    {{- $call = printf "new %s(%s)" .Target.ClassName (include "args" .)}}
+    {{- else if eq .Target.Kind "static"}}{{$call = printf "%s.%s()" .Target.ClassName (Ident .Target.MethodName)}}
+    {{- else if eq .Target.Kind "function"}}{{$call = printf "%s()" (Ident .Target.MethodName)}}
+    {{- end}}
+    {{- if not .Scenarios}}
+    it('should execute correctly', {{if .Target.Async}}async {{end}}() => {
+        {{- if eq .Target.Kind "instance"}}
+        // const sut = new {{.Target.ClassName}}();
+        {{- end}}
+        // const result = {{if .Target.Async}}await {{end}}{{$call}};
+        // expect(result).toBeDefined();
+    });
+    {{- end}}
 
-describe('{{.Target.ClassName}}', () => {
-    
     {{- range $s := .Scenarios}}
-    it('{{$s.Description}}', () => {
+    it({{$s.Description | Quote}}, {{if $.Target.Async}}async {{end}}() => {
         // Arrange
+        {{- range $name, $v := $s.Inputs}}
+        const {{VarName $name}} = {{FormatValue $v}};
+        {{- end}}
         {{- range $dep := $.Dependencies}}
-        const {{$dep.FieldName}} = {
-             {{- range $m := $s.MocksSetup}}
-                {{- if eq $m.Dependency $dep.FieldName}}
-                {{$m.Method}}: mock.fn(),
-                {{- end}}
-             {{- end}}
+        const {{$dep.FieldName | Ident}} = {
+            {{- range $m := $s.MocksSetup}}
+            {{- if eq $m.Dependency $dep.FieldName}}
+            {{- $sig := $.MethodOf $m.Dependency $m.Method}}
+            {{- if and $m.Error $sig.Async}}
+            {{$m.Method | Ident}}: jest.fn().mockRejectedValue(new Error({{$m.Error | Quote}})),
+            {{- else if $m.Error}}
+            {{$m.Method | Ident}}: jest.fn().mockImplementation(() => { throw new Error({{$m.Error | Quote}}); }),
+            {{- else}}
+            {{$m.Method | Ident}}: jest.fn().{{if $sig.Async}}mockResolvedValue{{else}}mockReturnValue{{end}}({{$m.ReturnValue | FormatAs $sig.Returns}}),
+            {{- end}}
+            {{- end}}
+            {{- end}}
         };
         {{- end}}
+        {{- if eq $.Target.Kind "instance"}}
 
-        {{- range $s.MocksSetup}}
-        // Configure Mock Return
-        {{.Dependency}}.{{.Method}}.mock.mockImplementation(() => {{.ReturnValue | FormatValue}});
+        // Init SUT
+        {{- template "sut" $}}
         {{- end}}
+        {{- if $s.Expectations.Error}}
 
-        // Init SUT
-        // const sut = new {{$.Target.ClassName}}({{range $i, $e := $.Dependencies}}{{if $i}}, {{end}}{{$e.FieldName}}{{end}});
+        // Act & Assert
+        {{- if $.Target.Async}}
+        // await expect({{$call}}).rejects.toThrow({{$s.Expectations.Error}});
+        {{- else}}
+        // expect(() => {{$call}}).toThrow({{$s.Expectations.Error}});
+        {{- end}}
+        {{- else}}
 
         // Act
-        // const result = sut.{{$.Target.MethodName}}();
+        // const result = {{if $.Target.Async}}await {{end}}{{$call}};
 
         // Assert
-        {{- if $s.Expectations.ReturnValue}}
-        // assert.strictEqual(result, {{$s.Expectations.ReturnValue | FormatValue}});
+        {{- if $s.Expectations.HasReturn}}
+        // expect(result).toBe({{$s.Expectations.ReturnValue | FormatValue}});
+        {{- end}}
         {{- end}}
     });
-    {{- end}}
-});`
+    {{- end}}`
 
-// --- FUNÇÃO CORE (Lógica Pura) ---
+// Builders de dados de teste, comuns ao node:test e ao Jest
+const nodeBuildersTmpl = `
+{{- range $t := .Builders}}
 
-func ProcessTemplate(config MetaFramework, lang string) (string, error) {
-	var tmplString string
-	switch strings.ToLower(lang) {
-	case "go", "golang":
-		tmplString = goTmpl
-	case "csharp", "cs","c#":
-		tmplString = csharpTmpl
-	case "kotlin", "kt":
-		tmplString = kotlinTmpl
-	case "java":
-		tmplString = javaTmpl
-	case "typescript", "ts":
-		tmplString = typeScriptTmpl
-	case "python", "py":
-		tmplString = pythonTmpl
-	case "php":
-		tmplString = phpTmpl
-    case "node", "js", "javascript":
-		tmplString = nodeTmpl
-	default:
-		return "", fmt.Errorf("unsupported language: %s", lang)
+function build{{$t.Name}}(overrides = {}) {
+    return {
+        {{- range $t.Fields}}
+        {{FieldName .Name}}: {{DefaultOf .}},
+        {{- end}}
+        ...overrides,
+    };
+}
+{{- end}}`
+
+// --- REGISTRO DE LINGUAGENS ---
+
+// Aliases aceitos em "lang"/"langs" apontando para o nome canônico
+var aliases = map[string]string{
+	"go":         "go",
+	"golang":     "go",
+	"csharp":     "csharp",
+	"cs":         "csharp",
+	"c#":         "csharp",
+	"kotlin":     "kotlin",
+	"kt":         "kotlin",
+	"java":       "java",
+	"typescript": "typescript",
+	"ts":         "typescript",
+	"python":     "python",
+	"py":         "python",
+	"php":        "php",
+	"node":       "node",
+	"js":         "node",
+	"javascript": "node",
+	"rust":       "rust",
+	"rs":         "rust",
+	"swift":      "swift",
+	"ruby":       "ruby",
+	"rb":         "ruby",
+	"cpp":        "cpp",
+	"c++":        "cpp",
+	"cxx":        "cpp",
+	"dart":       "dart",
+	"elixir":     "elixir",
+	"ex":         "elixir",
+	"exs":        "elixir",
+	"scala":      "scala",
+}
+
+// Template de cada linguagem canônica
+var templates = map[string]string{
+	"go":         goTmpl,
+	"csharp":     csharpTmpl,
+	"kotlin":     kotlinTmpl,
+	"java":       javaTmpl,
+	"typescript": typeScriptTmpl,
+	"python":     pythonTmpl,
+	"php":        phpTmpl,
+	"node":       nodeTmpl,
+	"rust":       rustTmpl,
+	"swift":      swiftTmpl,
+	"ruby":       rubyTmpl,
+	"cpp":        cppTmpl,
+	"dart":       dartTmpl,
+	"elixir":     elixirTmpl,
+	"scala":      scalaTmpl,
+}
+
+// ErrUnsupportedLanguage é devolvido (embrulhado) quando a linguagem pedida não existe.
+var ErrUnsupportedLanguage = errors.New("unsupported language")
+
+// CanonicalLang resolve um alias ("ts", "golang", "c#"...) para o nome canônico.
+func CanonicalLang(lang string) (string, bool) {
+	canonical, ok := aliases[strings.ToLower(lang)]
+	return canonical, ok
+}
+
+var funcMap = template.FuncMap{
+	"ToPascal": toPascal,
+	"ToLower": func(s string) string {
+		return strings.ToLower(s)
+	},
+	"ToCamel":          toCamel,
+	"ToSnake":          toSnake,
+	"ToKebab":          toKebab,
+	"ToScreamingSnake": toScreamingSnake,
+	"CppType":          cppType,
+	"MockType":         mockType,
+	"DartFake":         dartFake,
+	"DartFallback":     dartFallback,
+	"JavaMatcher":      javaMatcher,
+	"Comment":          comment,
+	"BacktickName":     backtickName,
+	// Substituídas pelas versões da linguagem em funcsFor
+	"FormatValue": jsSyntax.format,
+	"FormatAs":    func(_ string, v interface{}) string { return jsSyntax.format(v) },
+	"FieldName":   func(s string) string { return s },
+	"DefaultOf":   func(f Field) string { return jsSyntax.format(f.Default) },
+	"ZeroOf":      func(string) string { return jsSyntax.null },
+	"VarName":     func(s string) string { return s },
+	"Quote":       func(s string) string { return stringSyntax{quote: `"`}.literal(s, `"`) },
+	"Ident":       sanitizeIdent,
+	"Type":        func(s string) string { return s },
+	"Params":      func(params []Param) string { return paramList("", params) },
+	"include":     func(string, interface{}) (string, error) { return "", nil }, // Ligada ao template em withInclude
+	"Indent":      indent,
+}
+
+// --- CACHE DE TEMPLATES ---
+
+// Templates parseados uma única vez por linguagem.
+// *template.Template é seguro para Execute concorrente.
+var (
+	parsedMu  sync.Mutex
+	parsed    = map[string]*template.Template{}
+	overrides = map[string]string{} // Templates customizados (ex: -templates da CLI)
+)
+
+// TemplateSource devolve o texto do template usado para a linguagem,
+// já considerando um eventual template customizado.
+func TemplateSource(lang string) (string, bool) {
+	canonical, ok := CanonicalLang(lang)
+	if !ok {
+		return "", false
 	}
 
-	funcMap := template.FuncMap{
-		"ToPascal": func(s string) string {
-			return strings.ReplaceAll(strings.Title(strings.ReplaceAll(s, "_", " ")), " ", "")
-		},
-		"ToLower": func(s string) string {
-			return strings.ToLower(s)
-		},
-		"ToCamel": func(s string) string {
-			t := strings.ReplaceAll(strings.Title(strings.ReplaceAll(s, "_", " ")), " ", "")
-			if len(t) > 0 {
-				return strings.ToLower(t[0:1]) + t[1:]
-			}
-			return ""
-		},
-        "ToSnake": func(s string) string {
-            return strings.ReplaceAll(strings.ToLower(s), " ", "_")
-        },
-		"FormatValue": func(v interface{}) string {
-			switch val := v.(type) {
-			case string:
-				return fmt.Sprintf(`"%s"`, val)
-			default:
-				return fmt.Sprintf("%v", val)
-			}
-		},
+	parsedMu.Lock()
+	defer parsedMu.Unlock()
+	return sourceFor(canonical), true
+}
+
+func sourceFor(canonical string) string {
+	if source, ok := overrides[canonical]; ok {
+		return source
+	}
+	return templates[canonical]
+}
+
+// SetTemplateOverrides troca os templates embutidos pelos informados
+// (chave: linguagem ou alias). Chamar com um mapa vazio restaura os originais.
+// Nada é alterado se algum template for inválido.
+func SetTemplateOverrides(sources map[string]string) error {
+	next := map[string]string{}
+	for lang, source := range sources {
+		canonical, ok := CanonicalLang(lang)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrUnsupportedLanguage, lang)
+		}
+		if _, err := template.New(canonical).Funcs(funcsFor(canonical)).Parse(source); err != nil {
+			return fmt.Errorf("invalid template for %s: %v", lang, err)
+		}
+		next[canonical] = source
+	}
+
+	parsedMu.Lock()
+	defer parsedMu.Unlock()
+	overrides = next
+	parsed = map[string]*template.Template{}
+	return nil
+}
+
+func parsedTemplate(lang string) (*template.Template, error) {
+	canonical, ok := CanonicalLang(lang)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, lang)
+	}
+
+	parsedMu.Lock()
+	defer parsedMu.Unlock()
+
+	if t, ok := parsed[canonical]; ok {
+		return t, nil
 	}
 
-	t, err := template.New("code").Funcs(funcMap).Parse(tmplString)
+	t, err := withInclude(template.New(canonical).Funcs(funcsFor(canonical))).Parse(sourceFor(canonical))
 	if err != nil {
+		return nil, err
+	}
+	parsed[canonical] = t
+	return t, nil
+}
+
+// --- FUNÇÃO CORE (Lógica Pura) ---
+
+func ProcessTemplate(config MetaFramework, lang string) (string, error) {
+	t, err := parsedTemplate(lang)
+	if err != nil {
+		return "", err
+	}
+
+	config.Stack, err = config.Meta.StackFor(lang)
+	if err != nil {
+		return "", err
+	}
+	if config, err = config.withTargetMethods(); err != nil {
+		return "", err
+	}
+	if config, err = config.withInjection(); err != nil {
+		return "", err
+	}
+	if err := config.checkTypes(); err != nil {
 		return "", err
 	}
+	if err := config.checkTypeDefs(); err != nil {
+		return "", err
+	}
+	canonical, _ := CanonicalLang(lang)
+	if err := config.checkValues(canonical); err != nil {
+		return "", err
+	}
+	config.lang = canonical
+	config = config.withSafeIdentifiers(canonical)
+
+	if len(config.Meta.Naming) > 0 || len(config.Types) > 0 {
+		// Convenções e tipos da spec: uma cópia do template com as funções trocadas
+		naming, err := config.Meta.NamingFor(lang)
+		if err != nil {
+			return "", err
+		}
+		if t, err = t.Clone(); err != nil {
+			return "", err
+		}
+		withInclude(t.Funcs(languageFuncs(canonical, naming, config.Types)))
+	}
 
 	var buf strings.Builder
 	if err := t.Execute(&buf, config); err != nil {
@@ -503,4 +1995,63 @@ func ProcessTemplate(config MetaFramework, lang string) (string, error) {
 	}
 
 	return buf.String(), nil
-}
\ No newline at end of file
+}
+
+// --- NOMES DE ARQUIVO ---
+
+// Extensão de cada linguagem canônica
+var extensions = map[string]string{
+	"go":         "go",
+	"csharp":     "cs",
+	"kotlin":     "kt",
+	"java":       "java",
+	"php":        "php",
+	"typescript": "ts",
+	"node":       "test.js",
+	"python":     "py",
+	"rust":       "rs",
+	"swift":      "swift",
+	"ruby":       "rb",
+	"cpp":        "cpp",
+	"dart":       "dart",
+	"elixir":     "exs",
+	"scala":      "scala",
+}
+
+// Filename determina o nome do arquivo de teste seguindo a convenção da linguagem.
+func Filename(className, lang string) string {
+	// Mesmo nome de classe usado no código gerado (e nada de "/" ou ".." no caminho)
+	canonical, _ := CanonicalLang(lang)
+	className = sanitizeIdentFor(canonical, className)
+	ext, exists := extensions[canonical]
+	if !exists {
+		ext = "txt"
+	}
+
+	suffix := "Test"
+	// Exceções de nomenclatura
+	switch ext {
+	case "test.js":
+		suffix = ""
+	case "go":
+		suffix = "_test"
+	case "py":
+		return fmt.Sprintf("test_%s.py", toSnake(className))
+	case "rs":
+		return fmt.Sprintf("%s_test.rs", toSnake(className))
+	case "swift":
+		suffix = "Tests"
+	case "rb":
+		return fmt.Sprintf("%s_spec.rb", toSnake(className))
+	case "cpp":
+		return fmt.Sprintf("%s_test.cpp", toSnake(className))
+	case "dart":
+		return fmt.Sprintf("%s_test.dart", toSnake(className))
+	case "exs":
+		return fmt.Sprintf("%s_test.exs", toSnake(className))
+	case "scala":
+		return fmt.Sprintf("%sSpec.scala", className)
+	}
+
+	return fmt.Sprintf("%s%s.%s", className, suffix, ext)
+}
@@ -0,0 +1,245 @@
+package core
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// generate decodifica a spec e gera o teste da linguagem com o framework pedido.
+func generate(t *testing.T, spec, lang, framework string) string {
+	t.Helper()
+	var config MetaFramework
+	if err := json.Unmarshal([]byte(spec), &config); err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+	if framework != "" {
+		config.Meta.Frameworks = map[string]string{lang: framework}
+	}
+	code, err := ProcessTemplate(config, lang)
+	if err != nil {
+		t.Fatalf("ProcessTemplate(%s, %s): %v", lang, framework, err)
+	}
+	return code
+}
+
+// assertContains falha para cada trecho ausente do código gerado.
+func assertContains(t *testing.T, code string, want ...string) {
+	t.Helper()
+	for _, w := range want {
+		if !strings.Contains(code, w) {
+			t.Errorf("missing %q in:\n%s", w, code)
+		}
+	}
+}
+
+// assertNotContains falha para cada trecho presente no código gerado.
+func assertNotContains(t *testing.T, code string, unwanted ...string) {
+	t.Helper()
+	for _, u := range unwanted {
+		if strings.Contains(code, u) {
+			t.Errorf("unexpected %q in:\n%s", u, code)
+		}
+	}
+}
+
+// Um método com retorno e um void que falham, mais um não declarado
+const mockErrorSpec = `{
+  "target": {"class_name": "UserService", "method_name": "register"},
+  "types": [{"name": "User", "fields": [{"name": "id", "type": "int"}]}],
+  "dependencies": [{"field_name": "repo", "interface_name": "UserRepository", "methods": [
+    {"name": "find", "params": [{"name": "id", "type": "int"}], "returns": "User?"},
+    {"name": "save", "params": [{"name": "user", "type": "User"}]},
+    {"name": "count", "returns": "int"}
+  ]}],
+  "scenarios": [
+    {"id": "ok", "description": "registers", "mocks_setup": [
+      {"dependency": "repo", "method": "find", "return_value": {"id": 1}},
+      {"dependency": "repo", "method": "save"},
+      {"dependency": "repo", "method": "count", "return_value": 3},
+      {"dependency": "repo", "method": "ping", "return_value": true}
+    ]},
+    {"id": "down", "description": "fails", "mocks_setup": [
+      {"dependency": "repo", "method": "find", "error": "db down"},
+      {"dependency": "repo", "method": "save", "error": "disk full"},
+      {"dependency": "repo", "method": "ping", "error": "timeout"}
+    ], "expectations": {"error": "UserError.unavailable"}}
+  ]
+}`
+
+func TestGoMockErrors(t *testing.T) {
+	tests := []struct {
+		framework string
+		want      []string
+	}{
+		{"testify", []string{
+			`"errors"`,
+			"func (m *MockUserRepository) find(id int) (*User, error) {",
+			"return result, args.Error(1)",
+			"func (m *MockUserRepository) save(user *User) error {",
+			"return args.Error(0)",
+			"func (m *MockUserRepository) count() int {",
+			`repo.On("find", mock.Anything).Return(&User{ID: 1}, nil)`,
+			`repo.On("save", mock.Anything).Return(nil)`,
+			`repo.On("count").Return(3)`,
+			`repo.On("find", mock.Anything).Return(nil, errors.New("db down"))`,
+			`repo.On("save", mock.Anything).Return(errors.New("disk full"))`,
+			`repo.On("ping").Return(nil, errors.New("timeout"))`,
+			"// require.Error(t, err) // Expected: UserError.unavailable",
+		}},
+		{"gomock", []string{
+			`"errors"`,
+			`repo.EXPECT().find(gomock.Any()).Return(&User{ID: 1}, nil)`,
+			`repo.EXPECT().save(gomock.Any()).Return(nil)`,
+			`repo.EXPECT().count().Return(3)`,
+			`repo.EXPECT().find(gomock.Any()).Return(nil, errors.New("db down"))`,
+			`repo.EXPECT().save(gomock.Any()).Return(errors.New("disk full"))`,
+			`repo.EXPECT().ping(gomock.Any()).Return(nil, errors.New("timeout"))`,
+			"// if err == nil {",
+		}},
+		{"plain", []string{
+			`"errors"`,
+			"findErr error",
+			"func (s *stubUserRepository) find(id int) (*User, error) {",
+			"return s.findResult, s.findErr",
+			"func (s *stubUserRepository) save(user *User) error {",
+			"return s.saveErr",
+			"func (s *stubUserRepository) ping() (any, error) {",
+			`repo.findErr = errors.New("db down")`,
+			`repo.saveErr = errors.New("disk full")`,
+			`repo.pingErr = errors.New("timeout")`,
+			`// 	t.Fatal("expected UserError.unavailable")`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.framework, func(t *testing.T) {
+			code := generate(t, mockErrorSpec, "go", tt.framework)
+			assertContains(t, code, tt.want...)
+			assertNotContains(t, code, "Return(nil, nil)")
+		})
+	}
+}
+
+// Imports usados só em linhas comentadas não compilam; o parser descarta os comentários
+func TestGoImportsUsed(t *testing.T) {
+	golden, err := os.ReadFile(filepath.Join("testdata", "golden", "spec.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	specs := map[string]string{
+		"golden":      string(golden),
+		"mock errors": mockErrorSpec,
+		"no mocks":    `{"target": {"class_name": "Svc", "method_name": "run"}, "scenarios": [{"id": "ok", "expectations": {"error": "ErrBoom"}}]}`,
+	}
+
+	for name, spec := range specs {
+		for _, framework := range []string{"testify", "gomock", "plain"} {
+			t.Run(name+"/"+framework, func(t *testing.T) {
+				code := generate(t, spec, "go", framework)
+				file, err := parser.ParseFile(token.NewFileSet(), "", code, 0)
+				if err != nil {
+					t.Fatalf("generated code does not parse: %v\n%s", err, code)
+				}
+				used := map[string]bool{}
+				ast.Inspect(file, func(n ast.Node) bool {
+					if sel, ok := n.(*ast.SelectorExpr); ok {
+						if id, ok := sel.X.(*ast.Ident); ok {
+							used[id.Name] = true
+						}
+					}
+					return true
+				})
+				for _, imp := range file.Imports {
+					p, _ := strconv.Unquote(imp.Path.Value)
+					if !used[path.Base(p)] {
+						t.Errorf("import %s is only used in comments:\n%s", p, code)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGoWithoutMockErrors(t *testing.T) {
+	spec := `{"target": {"class_name": "Svc", "method_name": "run"},
+	  "dependencies": [{"field_name": "repo", "interface_name": "Repo", "methods": [{"name": "count", "returns": "int"}]}],
+	  "scenarios": [{"id": "ok", "mocks_setup": [{"dependency": "repo", "method": "count", "return_value": 1}]}]}`
+
+	for _, framework := range []string{"testify", "gomock", "plain"} {
+		t.Run(framework, func(t *testing.T) {
+			code := generate(t, spec, "go", framework)
+			assertNotContains(t, code, `"errors"`, ", error)", `"github.com/stretchr/testify/require"`)
+		})
+	}
+}
+
+func TestErrorExpectations(t *testing.T) {
+	tests := []struct {
+		lang, framework string
+		want            []string
+	}{
+		{"csharp", "xunit", []string{"// Assert.Throws<UserError.unavailable>(() => _sut.register());"}},
+		{"csharp", "nunit", []string{"// Assert.Throws<UserError.unavailable>(() => _sut.register());"}},
+		{"csharp", "mstest", []string{"// Assert.ThrowsException<UserError.unavailable>(() => _sut.register());"}},
+		{"java", "junit5", []string{"import static org.junit.jupiter.api.Assertions.assertThrows;", "// assertThrows(UserError.unavailable.class, () -> sut.register());"}},
+		{"java", "junit4", []string{"import static org.junit.Assert.assertThrows;"}},
+		{"java", "testng", []string{"import static org.testng.Assert.assertThrows;"}},
+		{"kotlin", "", []string{"import org.junit.jupiter.api.assertThrows", "// assertThrows<UserError.unavailable> { sut.register() }"}},
+		{"typescript", "jest", []string{"// expect(() => sut.register()).toThrow(UserError.unavailable);"}},
+		{"typescript", "mocha", []string{"// expect(() => sut.register()).to.throw(UserError.unavailable);"}},
+		{"node", "", []string{"// assert.throws(() => sut.register(), UserError.unavailable);"}},
+		{"node", "jest", []string{"// expect(() => sut.register()).toThrow(UserError.unavailable);"}},
+		{"php", "", []string{"// $this->expectException(UserError.unavailable::class);", "// $sut->register();"}},
+		{"rust", "", []string{"// assert!(result.is_err()); // Expected: UserError.unavailable"}},
+	}
+
+	async := strings.Replace(mockErrorSpec, `"method_name": "register"`, `"method_name": "register", "async": true`, 1)
+	asyncWant := map[string]string{
+		"csharp/xunit":    "// await Assert.ThrowsAsync<UserError.unavailable>(() => _sut.register());",
+		"csharp/mstest":   "// await Assert.ThrowsExceptionAsync<UserError.unavailable>(() => _sut.register());",
+		"java/junit5":     "// assertThrows(java.util.concurrent.CompletionException.class, () -> sut.register().join()); // Cause: UserError.unavailable",
+		"typescript/jest": "// await expect(sut.register()).rejects.toThrow(UserError.unavailable);",
+		"node/":           "// await assert.rejects(() => sut.register(), UserError.unavailable);",
+		"node/jest":       "// await expect(sut.register()).rejects.toThrow(UserError.unavailable);",
+	}
+
+	for _, tt := range tests {
+		name := tt.lang + "/" + tt.framework
+		t.Run(name, func(t *testing.T) {
+			assertContains(t, generate(t, mockErrorSpec, tt.lang, tt.framework), tt.want...)
+			if want, ok := asyncWant[name]; ok {
+				assertContains(t, generate(t, async, tt.lang, tt.framework), want)
+			}
+		})
+	}
+
+	ok := generate(t, `{"target": {"class_name": "Svc", "method_name": "run"}, "scenarios": [{"id": "ok"}]}`, "java", "")
+	assertNotContains(t, ok, "assertThrows")
+}
+
+func TestFalsyExpectations(t *testing.T) {
+	tests := []struct {
+		value, lang, want string
+	}{
+		{"false", "go", "want := false"},
+		{"0", "java", "// assertEquals(0, result);"},
+		{`""`, "python", `# self.assertEqual(result, "")`},
+		{"false", "typescript", "// expect(result).toBe(false);"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.value, func(t *testing.T) {
+			spec := `{"target": {"class_name": "Svc", "method_name": "run"},
+			  "scenarios": [{"id": "ok", "expectations": {"return_value": ` + tt.value + `}}]}`
+			assertContains(t, generate(t, spec, tt.lang, ""), tt.want)
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+)
+
+// --- CONVENÇÕES DE NOMES ---
+
+// Estilos de siglas em PascalCase/camelCase
+const (
+	AcronymsPreserve   = "preserve"   // "HTTPServer" continua "HTTPServer"
+	AcronymsCapitalize = "capitalize" // "HTTPServer" vira "HttpServer"
+)
+
+// NamingOptions ajusta a conversão de nomes de uma linguagem.
+// Campos vazios herdam o padrão da linguagem.
+type NamingOptions struct {
+	Acronyms    string   `json:"acronyms"`    // "preserve" ou "capitalize"
+	Initialisms []string `json:"initialisms"` // Palavras sempre em maiúsculas (ex: "ID", "URL" no Go)
+}
+
+// goInitialisms segue a lista do golint: "userId" vira "UserID" no Go.
+var goInitialisms = strings.Fields("ACL API ASCII CPU CSS DNS EOF GUID HTML HTTP HTTPS ID IP JSON LHS QPS RAM RHS RPC SLA SMTP SQL SSH TCP TLS TTL UDP UI UID UUID URI URL UTF8 VM XML XMPP XSRF XSS")
+
+// Padrões por linguagem canônica; as demais preservam siglas.
+var namingDefaults = map[string]NamingOptions{
+	"go":         {Acronyms: AcronymsPreserve, Initialisms: goInitialisms},
+	"csharp":     {Acronyms: AcronymsCapitalize},
+	"kotlin":     {Acronyms: AcronymsCapitalize},
+	"java":       {Acronyms: AcronymsCapitalize},
+	"typescript": {Acronyms: AcronymsCapitalize},
+	"node":       {Acronyms: AcronymsCapitalize},
+	"dart":       {Acronyms: AcronymsCapitalize},
+	"scala":      {Acronyms: AcronymsCapitalize},
+}
+
+// ErrInvalidNaming é devolvido (embrulhado) quando "meta.naming" tem um valor desconhecido.
+var ErrInvalidNaming = errors.New("invalid naming option")
+
+// NamingFor resolve as convenções de nomes da linguagem: o padrão embutido
+// sobrescrito por "naming" da spec (ex: {"go": {"acronyms": "capitalize"}}).
+func (m MetaInfo) NamingFor(lang string) (NamingOptions, error) {
+	canonical, ok := CanonicalLang(lang)
+	if !ok {
+		return NamingOptions{}, fmt.Errorf("%w: %s", ErrUnsupportedLanguage, lang)
+	}
+
+	opts := namingDefaults[canonical]
+	for key, custom := range m.Naming {
+		if c, _ := CanonicalLang(key); c != canonical {
+			continue
+		}
+		switch custom.Acronyms {
+		case "":
+		case AcronymsPreserve, AcronymsCapitalize:
+			opts.Acronyms = custom.Acronyms
+		default:
+			return NamingOptions{}, fmt.Errorf("%w for %s: acronyms %q (use %s or %s)", ErrInvalidNaming, canonical, custom.Acronyms, AcronymsPreserve, AcronymsCapitalize)
+		}
+		if custom.Initialisms != nil {
+			opts.Initialisms = custom.Initialisms
+		}
+	}
+	return opts, nil
+}
+
+// splitWords quebra um nome em palavras: qualquer caractere fora de letras e
+// dígitos separa, e dentro de um trecho a troca de caixa também
+// ("userId" -> user, Id; "HTTPServer" -> HTTP, Server; "getV2Data" -> get, V2, Data).
+func splitWords(s string) []string {
+	var words []string
+	for _, chunk := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(chunk)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, r := runes[i-1], runes[i]
+			if !unicode.IsUpper(r) {
+				continue
+			}
+			lowerBefore := unicode.IsLower(prev) || unicode.IsDigit(prev)
+			acronymEnd := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if lowerBefore || acronymEnd {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
+
+// isAcronym indica uma palavra de duas ou mais letras, todas maiúsculas ("HTTP", "ID").
+func isAcronym(word string) bool {
+	letters := 0
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	return letters > 1
+}
+
+func capitalize(word string) string {
+	r, size := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r)) + strings.ToLower(word[size:])
+}
+
+// title escreve uma palavra de PascalCase seguindo as opções da linguagem.
+func (n NamingOptions) title(word string) string {
+	for _, initialism := range n.Initialisms {
+		if strings.EqualFold(word, initialism) {
+			return strings.ToUpper(word)
+		}
+	}
+	if n.Acronyms != AcronymsCapitalize && isAcronym(word) {
+		return word
+	}
+	return capitalize(word)
+}
+
+func (n NamingOptions) pascal(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		b.WriteString(n.title(word))
+	}
+	return b.String()
+}
+
+func (n NamingOptions) camel(s string) string {
+	words := splitWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(strings.ToLower(words[0]))
+	for _, word := range words[1:] {
+		b.WriteString(n.title(word))
+	}
+	return b.String()
+}
+
+func joinWords(s, sep string, convert func(string) string) string {
+	words := splitWords(s)
+	for i := range words {
+		words[i] = convert(words[i])
+	}
+	return strings.Join(words, sep)
+}
+
+func toPascal(s string) string { return NamingOptions{}.pascal(s) }
+func toCamel(s string) string  { return NamingOptions{}.camel(s) }
+func toSnake(s string) string  { return joinWords(s, "_", strings.ToLower) }
+func toKebab(s string) string  { return joinWords(s, "-", strings.ToLower) }
+
+func toScreamingSnake(s string) string { return joinWords(s, "_", strings.ToUpper) }
+
+// funcs devolve as conversões de nomes com as opções aplicadas.
+func (n NamingOptions) funcs() template.FuncMap {
+	return template.FuncMap{
+		"ToPascal":         n.pascal,
+		"ToCamel":          n.camel,
+		"ToSnake":          toSnake,
+		"ToKebab":          toKebab,
+		"ToScreamingSnake": toScreamingSnake,
+	}
+}
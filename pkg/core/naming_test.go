@@ -0,0 +1,140 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSplitWords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"userId", []string{"user", "Id"}},
+		{"HTTPServer", []string{"HTTP", "Server"}},
+		{"ParseURL", []string{"Parse", "URL"}},
+		{"getV2Data", []string{"get", "V2", "Data"}},
+		{"user_name-id", []string{"user", "name", "id"}},
+		{"order total", []string{"order", "total"}},
+		{"ação_válida", []string{"ação", "válida"}},
+		{"ÉtatCivil", []string{"État", "Civil"}},
+		{"__", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := splitWords(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitWords(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCaseConversions(t *testing.T) {
+	tests := []struct {
+		name    string
+		convert func(string) string
+		in      string
+		want    string
+	}{
+		{"pascal", toPascal, "user_name", "UserName"},
+		{"pascal keeps acronyms", toPascal, "HTTPServer", "HTTPServer"},
+		{"pascal unicode", toPascal, "ação_válida", "AçãoVálida"},
+		{"camel", toCamel, "UserName", "userName"},
+		{"camel unicode", toCamel, "ação_válida", "açãoVálida"},
+		{"camel empty", toCamel, "", ""},
+		{"snake", toSnake, "HTTPServer", "http_server"},
+		{"kebab", toKebab, "getV2Data", "get-v2-data"},
+		{"screaming snake", toScreamingSnake, "userId", "USER_ID"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.convert(tt.in); got != tt.want {
+				t.Errorf("%s(%q) = %q, want %q", tt.name, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNamingInitialisms(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   string
+		naming map[string]NamingOptions
+		pascal map[string]string
+		camel  map[string]string
+	}{
+		{
+			name:   "go initialisms",
+			lang:   "go",
+			pascal: map[string]string{"user_id": "UserID", "parse_url": "ParseURL", "httpServer": "HTTPServer", "userId": "UserID"},
+			camel:  map[string]string{"user_id": "userID", "ID": "id", "api_key": "apiKey"},
+		},
+		{
+			name:   "java capitalizes acronyms",
+			lang:   "java",
+			pascal: map[string]string{"HTTPServer": "HttpServer", "userID": "UserId"},
+			camel:  map[string]string{"parseURL": "parseUrl"},
+		},
+		{
+			name:   "python preserves acronyms",
+			lang:   "py",
+			pascal: map[string]string{"HTTPServer": "HTTPServer", "user_id": "UserId"},
+		},
+		{
+			name:   "spec overrides by alias",
+			lang:   "go",
+			naming: map[string]NamingOptions{"golang": {Acronyms: AcronymsCapitalize, Initialisms: []string{}}},
+			pascal: map[string]string{"HTTPServer": "HttpServer", "user_id": "UserId"},
+		},
+		{
+			name:   "custom initialisms",
+			lang:   "csharp",
+			naming: map[string]NamingOptions{"cs": {Initialisms: []string{"IO"}}},
+			pascal: map[string]string{"file_io": "FileIO", "HTTPClient": "HttpClient"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts, err := MetaInfo{Naming: tt.naming}.NamingFor(tt.lang)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for in, want := range tt.pascal {
+				if got := opts.pascal(in); got != want {
+					t.Errorf("pascal(%q) = %q, want %q", in, got, want)
+				}
+			}
+			for in, want := range tt.camel {
+				if got := opts.camel(in); got != want {
+					t.Errorf("camel(%q) = %q, want %q", in, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNamingForErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		lang   string
+		naming map[string]NamingOptions
+		want   error
+	}{
+		{"unknown language", "cobol", nil, ErrUnsupportedLanguage},
+		{"unknown acronyms style", "go", map[string]NamingOptions{"go": {Acronyms: "lower"}}, ErrInvalidNaming},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := MetaInfo{Naming: tt.naming}.NamingFor(tt.lang)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("NamingFor(%q) error = %v, want %v", tt.lang, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package core
+
+// TEMPLATE RUBY (RSpec + verifying doubles)
+const rubyTmpl = `require 'spec_helper'
+# require_relative '../lib/{{.Target.ClassName | ToSnake}}'
+
+RSpec.describe {{.Target.ClassName}} do
+  {{- range .Dependencies}}
+  let(:{{.FieldName | ToSnake | Ident}}) { instance_double({{.InterfaceName}}) }
+  {{- end}}
+  {{- $deps := ""}}
+  {{- range $i, $e := .InjectedBy "constructor" "options"}}{{$deps = printf "%s%s%s" $deps (or (and $i ", ") "") (Ident (ToSnake $e.FieldName))}}{{end}}
+  {{- if not .NeedsSut}}
+  {{- else if eq .Target.Injection "container"}}
+  # before do
+  #   Container.enable_stubs!
+  {{- range .Dependencies}}
+  #   Container.stub({{.FieldName | ToSnake | Quote}}, {{.FieldName | ToSnake | Ident}})
+  {{- end}}
+  # end
+  # subject(:sut) { described_class.new }
+  {{- else}}
+  {{- $new := printf "described_class.new(%s)" $deps}}
+  {{- if eq .Target.Injection "factory"}}{{$new = printf "%s(%s)" (or .Target.Factory "described_class.create") $deps}}{{end}}
+  {{- $props := ""}}
+  {{- range $i, $e := .InjectedBy "property"}}{{$props = printf "%s%ss.%s = %s" $props (or (and $i "; ") "") (ToSnake $e.FieldName) (Ident (ToSnake $e.FieldName))}}{{end}}
+  # subject(:sut) { {{$new}}{{if $props}}.tap { |s| {{$props}} }{{end}} }
+  {{- end}}
+  {{- range $g := .Groups}}
+  {{- $name := printf "#%s" (ToSnake .Target.MethodName)}}
+  {{- $call := printf "sut.%s" (Ident (ToSnake .Target.MethodName))}}
+  {{- if eq .Target.Kind "constructor"}}{{$name = ".new"}}{{$call = printf "described_class.new(%s)" $deps}}
+  {{- else if eq .Target.Kind "static"}}{{$name = printf ".%s" (ToSnake .Target.MethodName)}}{{$call = printf "described_class.%s" (Ident (ToSnake .Target.MethodName))}}
+  {{- else if eq .Target.Kind "function"}}{{$name = ToSnake .Target.MethodName}}{{$call = Ident (ToSnake .Target.MethodName)}}
+  {{- end}}
+
+  describe {{$name | Quote}} do
+    {{- if not .Scenarios}}
+    it 'executes correctly' do
+      # result = {{$call}}
+      # expect(result).not_to be_nil
+    end
+    {{- end}}
+
+    {{- range $i, $s := .Scenarios}}
+    {{- if $i}}
+{{end}}
+    it {{$s.Description | Quote}} do
+      # Arrange
+      {{- range $name, $v := $s.Inputs}}
+      {{VarName $name}} = {{FormatValue $v}}
+      {{- end}}
+      {{- range $m := $s.MocksSetup}}
+      allow({{.Dependency | ToSnake | Ident}}).to receive(:{{.Method | ToSnake}}){{if .Error}}.and_raise(StandardError, {{.Error | Quote}}){{else}}.and_return({{.ReturnValue | FormatAs ($.MethodOf .Dependency .Method).Returns}}){{end}}
+      {{- end}}
+
+      {{- if $s.Expectations.Error}}
+
+      # Act & Assert
+      # expect { {{$call}} }.to raise_error({{$s.Expectations.Error}})
+      {{- else}}
+
+      # Act
+      # result = {{$call}}
+
+      # Assert
+      {{- if $s.Expectations.HasReturn}}
+      # expect(result).to eq({{$s.Expectations.ReturnValue | FormatValue}})
+      {{- end}}
+      {{- end}}
+    end
+    {{- end}}
+  end
+  {{- end}}
+  {{- range $t := .Builders}}
+
+  def build_{{$t.Name | ToSnake}}(**overrides)
+    {{$t.Name}}.new(**{
+      {{- range $i, $f := $t.Fields}}{{if $i}},{{end}}
+      {{FieldName $f.Name}}: {{DefaultOf $f}}
+      {{- end}}
+    }.merge(overrides))
+  end
+  {{- end}}
+end
+`
@@ -0,0 +1,309 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// --- STRINGS E IDENTIFICADORES ---
+
+// stringSyntax descreve como textos livres da spec (descrições, nomes,
+// valores) viram literais de string seguros em uma linguagem.
+type stringSyntax struct {
+	quote     string          // Delimitador usado pelos templates (it('...'), t.Run("..."))
+	singleRaw bool            // Aspas simples sem escapes além de \' e \\ (PHP, Ruby)
+	extra     map[rune]string // Escapes além de \\, aspas e quebras de linha (ex: "$" no Kotlin)
+	interp    string          // Abertura de interpolação em aspas duplas (ex: "#{" no Ruby)
+}
+
+var stringSyntaxes = map[string]stringSyntax{
+	"go":         {quote: `"`},
+	"csharp":     {quote: `"`},
+	"kotlin":     {quote: `"`, extra: map[rune]string{'$': `\$`}},
+	"java":       {quote: `"`},
+	"php":        {quote: `'`, singleRaw: true, extra: map[rune]string{'$': `\$`}},
+	"typescript": {quote: `'`},
+	"node":       {quote: `'`},
+	"python":     {quote: `"`},
+	"rust":       {quote: `"`},
+	"swift":      {quote: `"`},
+	"ruby":       {quote: `'`, singleRaw: true, interp: "#{"},
+	"cpp":        {quote: `"`},
+	"dart":       {quote: `'`, extra: map[rune]string{'$': `\$`}},
+	"elixir":     {quote: `"`, interp: "#{"},
+	"scala":      {quote: `"`},
+}
+
+func stringSyntaxFor(lang string) stringSyntax {
+	if ss, ok := stringSyntaxes[lang]; ok {
+		return ss
+	}
+	return stringSyntax{quote: `"`}
+}
+
+// literal devolve "s" como literal de string delimitado por "quote".
+func (ss stringSyntax) literal(s, quote string) string {
+	var b strings.Builder
+	b.WriteString(quote)
+
+	if quote == "'" && ss.singleRaw {
+		// Aspas simples não interpretam \n; quebras de linha e outros
+		// controles só sobrevivem como escapes dentro de aspas duplas
+		if strings.ContainsFunc(s, unicode.IsControl) {
+			return ss.literal(s, `"`)
+		}
+		for _, r := range s {
+			if r == '\\' || r == '\'' {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteString(quote)
+		return b.String()
+	}
+
+	for i, r := range s {
+		if ss.interp != "" && strings.HasPrefix(s[i:], ss.interp) {
+			b.WriteRune('\\')
+		}
+		if esc, ok := ss.extra[r]; ok {
+			b.WriteString(esc)
+			continue
+		}
+		switch {
+		case r == '\\':
+			b.WriteString(`\\`)
+		case string(r) == quote:
+			b.WriteString(`\` + quote)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case unicode.IsControl(r):
+			b.WriteRune(' ')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteString(quote)
+	return b.String()
+}
+
+// comment achata um texto em uma linha, para uso dentro de comentários.
+func comment(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// backtickName limpa um texto para nomes entre crases (funções de teste em Kotlin).
+// Crases, quebras de linha e os caracteres proibidos pela JVM viram espaço.
+func backtickName(s string) string {
+	clean := strings.Map(func(r rune) rune {
+		if strings.ContainsRune("`.;[]/<>:\\", r) || unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+	return comment(clean)
+}
+
+// --- IDENTIFICADORES ---
+
+var (
+	invalidIdentRe      = regexp.MustCompile(`[^\p{L}\p{N}_]+`)
+	invalidASCIIIdentRe = regexp.MustCompile(`[^A-Za-z0-9_]+`)
+)
+
+// Linguagens em que identificadores só podem ter letras ASCII
+var asciiIdents = words("go cpp dart elixir rust")
+
+// Letras latinas acentuadas e a letra base usada nos identificadores ASCII
+var accentBase = func() map[rune]rune {
+	bases := map[rune]string{
+		'a': "áàâãäå", 'e': "éèêë", 'i': "íìîï", 'o': "óòôõö", 'u': "úùûü", 'c': "ç", 'n': "ñ", 'y': "ýÿ",
+		'A': "ÁÀÂÃÄÅ", 'E': "ÉÈÊË", 'I': "ÍÌÎÏ", 'O': "ÓÒÔÕÖ", 'U': "ÚÙÛÜ", 'C': "Ç", 'N': "Ñ", 'Y': "Ý",
+	}
+	m := map[rune]rune{}
+	for base, accented := range bases {
+		for _, r := range accented {
+			m[r] = base
+		}
+	}
+	return m
+}()
+
+// sanitizeIdent troca caracteres inválidos por "_" e evita iniciar com dígito.
+// Aceita letras Unicode; ver sanitizeIdentFor para as linguagens só ASCII.
+func sanitizeIdent(s string) string {
+	return sanitizeIdentFor("", s)
+}
+
+// sanitizeIdentFor é o sanitizeIdent da linguagem: nas linguagens de asciiIdents
+// os acentos caem (ação → acao) e as demais letras não ASCII viram "_".
+func sanitizeIdentFor(lang, s string) string {
+	re := invalidIdentRe
+	if asciiIdents[lang] {
+		s = strings.Map(func(r rune) rune {
+			if base, ok := accentBase[r]; ok {
+				return base
+			}
+			return r
+		}, s)
+		re = invalidASCIIIdentRe
+	}
+	id := re.ReplaceAllString(strings.TrimSpace(s), "_")
+	if id == "" {
+		return "_"
+	}
+	if unicode.IsDigit([]rune(id)[0]) {
+		return "_" + id
+	}
+	return id
+}
+
+// asciiType aplica aos tipos da aplicação (User, models.User) a regra dos
+// identificadores ASCII; nas demais linguagens o tipo fica como está.
+func asciiType(lang string, t typeRef) typeRef {
+	if !asciiIdents[lang] {
+		return t
+	}
+	args := make([]typeRef, len(t.args))
+	for i, arg := range t.args {
+		args[i] = asciiType(lang, arg)
+	}
+	t.args = args
+	if !primitiveTypes[t.name] {
+		parts := strings.Split(t.name, ".")
+		for i := range parts {
+			parts[i] = sanitizeIdentFor(lang, parts[i])
+		}
+		t.name = strings.Join(parts, ".")
+	}
+	return t
+}
+
+// keywordStyle define como uma palavra reservada vira identificador válido.
+type keywordStyle struct {
+	words  map[string]bool
+	escape func(id string) string
+}
+
+func words(list string) map[string]bool {
+	set := map[string]bool{}
+	for _, w := range strings.Fields(list) {
+		set[w] = true
+	}
+	return set
+}
+
+func suffixed(id string) string   { return id + "_" }
+func backticked(id string) string { return "`" + id + "`" }
+
+var keywordStyles = map[string]keywordStyle{
+	"go": {words("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var"), suffixed},
+	"csharp": {words("abstract as base bool break byte case catch char checked class const continue decimal default delegate do double else enum event explicit extern false finally fixed float for foreach goto if implicit in int interface internal is lock long namespace new null object operator out override params private protected public readonly ref return sbyte sealed short sizeof stackalloc static string struct switch this throw true try typeof uint ulong unchecked unsafe ushort using virtual void volatile while"),
+		func(id string) string { return "@" + id }},
+	"kotlin":     {words("as break class continue do else false for fun if in interface is null object package return super this throw true try typealias typeof val var when while"), backticked},
+	"java":       {words("abstract assert boolean break byte case catch char class const continue default do double else enum extends final finally float for goto if implements import instanceof int interface long native new package private protected public return short static strictfp super switch synchronized this throw throws transient try void volatile while true false null var record yield"), suffixed},
+	"php":        {words("abstract and array as break callable case catch class clone const continue declare default do echo else elseif empty enddeclare endfor endforeach endif endswitch endwhile eval exit extends final finally fn for foreach function global goto if implements include instanceof insteadof interface isset list match namespace new or print private protected public readonly require return static switch throw trait try unset use var while xor yield"), suffixed},
+	"typescript": {words(jsKeywords + " enum implements interface package private protected public type"), suffixed},
+	"node":       {words(jsKeywords), suffixed},
+	"python":     {words("False None True and as assert async await break class continue def del elif else except finally for from global if import in is lambda nonlocal not or pass raise return try while with yield"), suffixed},
+	"rust": {words("as async await break const continue crate dyn else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while"),
+		func(id string) string {
+			// self, Self, super e crate não aceitam a forma r#
+			switch id {
+			case "self", "Self", "super", "crate":
+				return id + "_"
+			}
+			return "r#" + id
+		}},
+	"swift":  {words("associatedtype class deinit enum extension fileprivate func import init inout internal let open operator private protocol public rethrows static struct subscript typealias var break case continue default defer do else fallthrough for guard if in repeat return switch where while as Any catch false is nil super self Self throw throws true try"), backticked},
+	"ruby":   {words("BEGIN END alias and begin break case class def defined? do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield"), suffixed},
+	"cpp":    {words("alignas alignof and asm auto bool break case catch char class const constexpr continue decltype default delete do double else enum explicit export extern false float for friend goto if inline int long mutable namespace new noexcept not nullptr operator or private protected public register return short signed sizeof static struct switch template this throw true try typedef typeid typename union unsigned using virtual void volatile while"), suffixed},
+	"dart":   {words("abstract as assert async await break case catch class const continue default deferred do dynamic else enum export extends external factory false final finally for get if implements import in is late library mixin new null operator part required rethrow return set static super switch this throw true try typedef var void while with yield"), suffixed},
+	"elixir": {words("after and catch do else end false fn in nil not or rescue true when"), suffixed},
+	"scala":  {words("abstract case catch class def do else extends false final finally for forSome given if implicit import lazy match new null object override package private protected return sealed super this throw trait true try type val var while with yield"), backticked},
+}
+
+const jsKeywords = "break case catch class const continue debugger default delete do else export extends false finally for function if import in instanceof let new null return static super switch this throw true try typeof var void while with yield await"
+
+// ident torna "s" um identificador válido na linguagem, escapando palavras reservadas.
+func ident(lang, s string) string {
+	id := sanitizeIdentFor(lang, s)
+	if style, ok := keywordStyles[lang]; ok && style.words[id] {
+		return style.escape(id)
+	}
+	return id
+}
+
+// withSafeIdentifiers devolve uma cópia da spec com os nomes usados como código
+// (classe, método, dependências, métodos e parâmetros) reduzidos a caracteres válidos.
+// Palavras reservadas são escapadas nos templates, com Ident, depois das
+// conversões de caixa (ToSnake, ToPascal) que cada linguagem aplica.
+func (m MetaFramework) withSafeIdentifiers(lang string) MetaFramework {
+	safe := func(s string) string {
+		if s == "" {
+			return s
+		}
+		return sanitizeIdentFor(lang, s)
+	}
+
+	m.Target.ClassName = safe(m.Target.ClassName)
+	m.Target.MethodName = safe(m.Target.MethodName)
+
+	safeScenarios := func(scenarios []Scenario) []Scenario {
+		safeCopy := make([]Scenario, len(scenarios))
+		for i, s := range scenarios {
+			mocks := make([]MockSetup, len(s.MocksSetup))
+			for j, mock := range s.MocksSetup {
+				mock.Dependency = safe(mock.Dependency)
+				mock.Method = safe(mock.Method)
+				mocks[j] = mock
+			}
+			s.MocksSetup = mocks
+			safeCopy[i] = s
+		}
+		return safeCopy
+	}
+
+	if len(m.Target.Methods) > 0 {
+		methods := make([]TargetMethod, len(m.Target.Methods))
+		for i, method := range m.Target.Methods {
+			method.Name = safe(method.Name)
+			method.Scenarios = safeScenarios(method.Scenarios)
+			methods[i] = method
+		}
+		m.Target.Methods = methods
+	}
+
+	deps := make([]Dependency, len(m.Dependencies))
+	for i, dep := range m.Dependencies {
+		dep.FieldName = safe(dep.FieldName)
+		dep.InterfaceName = safe(dep.InterfaceName)
+		methods := make([]Method, len(dep.Methods))
+		for j, method := range dep.Methods {
+			method.Name = safe(method.Name)
+			method.Declared = true
+			params := make([]Param, len(method.Params))
+			for k, p := range method.Params {
+				if p.Name == "" {
+					p.Name = fmt.Sprintf("arg%d", k+1)
+				}
+				p.Name = safe(p.Name)
+				params[k] = p
+			}
+			method.Params = params
+			methods[j] = method
+		}
+		dep.Methods = methods
+		deps[i] = dep
+	}
+	m.Dependencies = deps
+
+	m.Scenarios = safeScenarios(m.Scenarios)
+	return m
+}
@@ -0,0 +1,139 @@
+package core
+
+import "testing"
+
+func TestLiteral(t *testing.T) {
+	tests := []struct {
+		lang, in, want string
+	}{
+		{"go", `say "hi"`, `"say \"hi\""`},
+		{"go", `a\b`, `"a\\b"`},
+		{"go", "line\nnext\tend\r", `"line\nnext\tend\r"`},
+		{"go", "bell\a", `"bell "`},
+		{"go", "ação ✓", `"ação ✓"`},
+		{"kotlin", "costs $5", `"costs \$5"`},
+		{"dart", "it's $name", `'it\'s \$name'`},
+		{"dart", "line\nnext", `'line\nnext'`},
+		{"typescript", "it's", `'it\'s'`},
+		{"python", `C:\tmp "x"`, `"C:\\tmp \"x\""`},
+		{"elixir", "#{user}", `"\#{user}"`},
+		{"elixir", "# not interpolated {x}", `"# not interpolated {x}"`},
+		{"php", "it's", `'it\'s'`},
+		{"php", `a\b`, `'a\\b'`},
+		{"php", "costs $5", `'costs $5'`},
+		{"php", "costs $5\nnext", `"costs \$5\nnext"`},
+		{"ruby", "#{user} it's", `'#{user} it\'s'`},
+		{"ruby", "#{user}\nnext", `"\#{user}\nnext"`},
+		{"ruby", "tab\there", `"tab\there"`},
+		{"ruby", "ação ✓", `'ação ✓'`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.in, func(t *testing.T) {
+			ss := stringSyntaxFor(tt.lang)
+			if got := ss.literal(tt.in, ss.quote); got != tt.want {
+				t.Errorf("literal(%q) in %s = %s, want %s", tt.in, tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeIdentFor(t *testing.T) {
+	tests := []struct {
+		lang, in, want string
+	}{
+		{"go", "ação", "acao"},
+		{"rust", "Ça va", "Ca_va"},
+		{"java", "ação", "ação"},
+		{"python", "user-id", "user_id"},
+		{"kotlin", "  order.total  ", "order_total"},
+		{"csharp", "1st", "_1st"},
+		{"swift", "", "_"},
+		{"go", "import", "import"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.in, func(t *testing.T) {
+			if got := sanitizeIdentFor(tt.lang, tt.in); got != tt.want {
+				t.Errorf("sanitizeIdentFor(%q, %q) = %q, want %q", tt.lang, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdent(t *testing.T) {
+	tests := []struct {
+		lang, in, want string
+	}{
+		{"go", "import", "import_"},
+		{"go", "class", "class"},
+		{"csharp", "class", "@class"},
+		{"kotlin", "object", "`object`"},
+		{"java", "import", "import_"},
+		{"php", "list", "list_"},
+		{"typescript", "type", "type_"},
+		{"node", "delete", "delete_"},
+		{"python", "from", "from_"},
+		{"rust", "type", "r#type"},
+		{"rust", "self", "self_"},
+		{"swift", "import", "`import`"},
+		{"ruby", "end", "end_"},
+		{"cpp", "class", "class_"},
+		{"dart", "import", "import_"},
+		{"elixir", "do", "do_"},
+		{"scala", "type", "`type`"},
+		{"go", "user-id", "user_id"},
+		{"go", "ação", "acao"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.in, func(t *testing.T) {
+			if got := ident(tt.lang, tt.in); got != tt.want {
+				t.Errorf("ident(%q, %q) = %q, want %q", tt.lang, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+// Método de dependência e do target com nomes reservados
+const keywordSpec = `{
+  "target": {"class_name": "Loader", "method_name": "class"},
+  "dependencies": [{"field_name": "repo", "interface_name": "Repo", "methods": [
+    {"name": "import", "params": [{"name": "id", "type": "int"}], "returns": "int"}
+  ]}],
+  "scenarios": [{"id": "ok", "description": "loads", "mocks_setup": [
+    {"dependency": "repo", "method": "import", "return_value": 1},
+    {"dependency": "repo", "method": "delete", "return_value": true}
+  ]}]
+}`
+
+func TestKeywordMethodNames(t *testing.T) {
+	tests := []struct {
+		lang, framework string
+		want            []string
+	}{
+		{"go", "testify", []string{"func (m *MockRepo) import_(id int) int {", `repo.On("import_", mock.Anything)`, "sut.class()"}},
+		{"go", "gomock", []string{"repo.EXPECT().import_(gomock.Any())"}},
+		{"go", "plain", []string{"func (s *stubRepo) import_(id int) int {"}},
+		{"csharp", "", []string{"_sut.@class()"}},
+		{"kotlin", "", []string{"sut.`class`()"}},
+		{"java", "", []string{"when(repo.import_(anyInt()))", "sut.class_()"}},
+		{"php", "", []string{"->method('import')", "$sut->class_()"}},
+		{"typescript", "", []string{"import_: jest.fn", "delete_: jest.fn()", "repo.import_.mockReturnValue(1)", "repo.delete_.mockReturnValue(true)", "sut.class_()"}},
+		{"node", "", []string{"import_: mock.fn()", "repo.import_.mock.mockImplementation", "sut.class_()"}},
+		{"node", "jest", []string{"import_: jest.fn()", "delete_: jest.fn()", "sut.class_()"}},
+		{"python", "", []string{"self.mock_repo.import_.return_value = 1", "self.sut.class_()"}},
+		{"python", "pytest", []string{"repo.import_.return_value = 1", "sut.class_()"}},
+		{"swift", "", []string{"func `import`(id: Int)", "sut.`class`()"}},
+		{"ruby", "", []string{"sut.class_"}},
+		{"cpp", "", []string{"MOCK_METHOD(bool, delete_, (), (override));", "EXPECT_CALL(repo, delete_())", "sut->class_()"}},
+		{"dart", "", []string{"repo.import_(any())", "sut.class_()"}},
+		{"scala", "", []string{"repo.`import`(any[Int])", "sut.`class`()"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.framework, func(t *testing.T) {
+			assertContains(t, generate(t, keywordSpec, tt.lang, tt.framework), tt.want...)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// --- MÉTODOS DO TARGET ---
+
+// ErrInvalidTarget indica um "target" inconsistente (ex: método sem nome ou repetido).
+var ErrInvalidTarget = errors.New("invalid target")
+
+// Tipos de target: como o teste chama o código testado.
+const (
+	KindInstance    = "instance"    // sut.metodo(), com o SUT construído no setup
+	KindStatic      = "static"      // Classe.metodo(), sem SUT
+	KindFunction    = "function"    // metodo(), função livre
+	KindConstructor = "constructor" // new Classe(deps): o próprio construtor é testado
+)
+
+// TargetMethod é um método da classe testada com os próprios cenários.
+// Cada um vira um grupo no arquivo (describe, classe aninhada, Test<Método>...).
+type TargetMethod struct {
+	Name      string     `json:"name"`
+	Async     bool       `json:"async"`
+	Kind      string     `json:"kind"` // Vazio herda o "kind" do target
+	Scenarios []Scenario `json:"scenarios"`
+}
+
+// targetKind valida um "kind", usando fallback quando vazio.
+func targetKind(kind, fallback string) (string, error) {
+	switch kind {
+	case "":
+		return fallback, nil
+	case KindInstance, KindStatic, KindFunction, KindConstructor:
+		return kind, nil
+	}
+	return "", fmt.Errorf("%w: kind %q (use instance, static, function or constructor)", ErrInvalidTarget, kind)
+}
+
+// withTargetMethods normaliza o target: "kind" vazio vira instance (nos métodos,
+// o do target). Com "target.methods", o par legado method_name/scenarios, se
+// presente, vira o primeiro método; Scenarios passa a reunir os cenários de todos
+// os métodos (validações, mocks usados, imports) e Target.Async fica verdadeiro
+// se algum método for assíncrono.
+func (m MetaFramework) withTargetMethods() (MetaFramework, error) {
+	kind, err := targetKind(m.Target.Kind, KindInstance)
+	if err != nil {
+		return m, err
+	}
+	m.Target.Kind = kind
+	if len(m.Target.Methods) == 0 {
+		return m, nil
+	}
+
+	declared := make([]TargetMethod, len(m.Target.Methods))
+	for i, method := range m.Target.Methods {
+		if method.Name == "" {
+			return m, fmt.Errorf("%w: methods[%d] has no name", ErrInvalidTarget, i)
+		}
+		if method.Kind, err = targetKind(method.Kind, kind); err != nil {
+			return m, err
+		}
+		declared[i] = method
+	}
+
+	if m.Target.MethodName == "" && len(m.Scenarios) > 0 {
+		return m, fmt.Errorf("%w: top-level scenarios need a method_name when methods is set", ErrInvalidTarget)
+	}
+
+	var methods []TargetMethod
+	if m.Target.MethodName != "" {
+		methods = append(methods, TargetMethod{Name: m.Target.MethodName, Async: m.Target.Async, Kind: kind, Scenarios: m.Scenarios})
+	}
+	methods = append(methods, declared...)
+
+	seen := map[string]bool{}
+	m.Scenarios = nil
+	for _, method := range methods {
+		if seen[method.Name] {
+			return m, fmt.Errorf("%w: duplicate method %q", ErrInvalidTarget, method.Name)
+		}
+		seen[method.Name] = true
+		m.Scenarios = append(m.Scenarios, method.Scenarios...)
+		m.Target.Async = m.Target.Async || method.Async
+	}
+	m.Target.MethodName = methods[0].Name
+	m.Target.Methods = methods
+	return m, nil
+}
+
+// Groups devolve uma visão da spec por método do target, com MethodName, Async e
+// Scenarios do método. Sem "methods", devolve a própria spec (um único grupo).
+// Nas visões, Target.Methods continua preenchido: os templates o usam para saber
+// se estão dentro de um grupo.
+func (m MetaFramework) Groups() []MetaFramework {
+	if len(m.Target.Methods) == 0 {
+		return []MetaFramework{m}
+	}
+	groups := make([]MetaFramework, len(m.Target.Methods))
+	for i, method := range m.Target.Methods {
+		group := m
+		group.Target.MethodName, group.Target.Async = method.Name, method.Async
+		group.Target.Kind = method.Kind
+		group.Scenarios = method.Scenarios
+		groups[i] = group
+	}
+	return groups
+}
+
+// allScenarios reúne os cenários de todos os métodos; numa visão de Groups,
+// Scenarios tem só os do grupo.
+func (m MetaFramework) allScenarios() []Scenario {
+	if len(m.Target.Methods) == 0 {
+		return m.Scenarios
+	}
+	var scenarios []Scenario
+	for _, method := range m.Target.Methods {
+		scenarios = append(scenarios, method.Scenarios...)
+	}
+	return scenarios
+}
+
+// NeedsSut informa se algum grupo chama métodos de instância, ou seja, se o
+// setup compartilhado do arquivo deve construir o SUT.
+func (m MetaFramework) NeedsSut() bool {
+	for _, group := range m.Groups() {
+		if group.Target.Kind == KindInstance || group.Target.Kind == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// withInclude liga a função "include" ao próprio template: executa um bloco
+// {{define}} e devolve o texto, que pode ser recuado com Indent.
+func withInclude(t *template.Template) *template.Template {
+	return t.Funcs(template.FuncMap{"include": func(name string, data interface{}) (string, error) {
+		var buf strings.Builder
+		err := t.ExecuteTemplate(&buf, name, data)
+		return buf.String(), err
+	}})
+}
+
+// indent recua em n espaços as linhas não vazias de s.
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
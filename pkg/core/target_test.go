@@ -0,0 +1,124 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithTargetMethods(t *testing.T) {
+	ok := []Scenario{{ID: "ok"}}
+	fails := []Scenario{{ID: "fails"}}
+	tests := []struct {
+		name    string
+		target  TargetInfo
+		legacy  []Scenario
+		methods []string
+		kinds   []string
+		async   bool
+		wantErr bool
+	}{
+		{"legacy only", TargetInfo{MethodName: "run"}, ok, nil, nil, false, false},
+		{"methods only", TargetInfo{Methods: []TargetMethod{
+			{Name: "find", Scenarios: ok},
+			{Name: "save", Async: true, Kind: KindStatic, Scenarios: fails},
+		}}, nil, []string{"find", "save"}, []string{KindInstance, KindStatic}, true, false},
+		{"legacy becomes first method", TargetInfo{MethodName: "run", Kind: KindFunction, Methods: []TargetMethod{
+			{Name: "stop"},
+		}}, ok, []string{"run", "stop"}, []string{KindFunction, KindFunction}, false, false},
+		{"method without name", TargetInfo{Methods: []TargetMethod{{Name: ""}}}, nil, nil, nil, false, true},
+		{"duplicate method", TargetInfo{MethodName: "run", Methods: []TargetMethod{{Name: "run"}}}, nil, nil, nil, false, true},
+		{"top-level scenarios without method_name", TargetInfo{Methods: []TargetMethod{{Name: "run"}}}, ok, nil, nil, false, true},
+		{"unknown kind", TargetInfo{Methods: []TargetMethod{{Name: "run", Kind: "lambda"}}}, nil, nil, nil, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MetaFramework{Target: tt.target, Scenarios: tt.legacy}.withTargetMethods()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTarget) {
+					t.Fatalf("withTargetMethods() = %v, want %v", err, ErrInvalidTarget)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var names, kinds []string
+			for _, method := range got.Target.Methods {
+				names, kinds = append(names, method.Name), append(kinds, method.Kind)
+			}
+			if !reflect.DeepEqual(names, tt.methods) || !reflect.DeepEqual(kinds, tt.kinds) {
+				t.Errorf("methods = %v %v, want %v %v", names, kinds, tt.methods, tt.kinds)
+			}
+			if got.Target.Async != tt.async {
+				t.Errorf("Target.Async = %v, want %v", got.Target.Async, tt.async)
+			}
+			if len(tt.methods) > 0 && got.Target.MethodName != tt.methods[0] {
+				t.Errorf("Target.MethodName = %q, want %q", got.Target.MethodName, tt.methods[0])
+			}
+		})
+	}
+}
+
+func TestGroups(t *testing.T) {
+	spec, err := MetaFramework{Target: TargetInfo{Methods: []TargetMethod{
+		{Name: "find", Scenarios: []Scenario{{ID: "a"}, {ID: "b"}}},
+		{Name: "save", Async: true, Kind: KindStatic, Scenarios: []Scenario{{ID: "c"}}},
+	}}}.withTargetMethods()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	groups := spec.Groups()
+	if len(groups) != 2 {
+		t.Fatalf("Groups() = %d groups, want 2", len(groups))
+	}
+	save := groups[1]
+	if save.Target.MethodName != "save" || !save.Target.Async || save.Target.Kind != KindStatic {
+		t.Errorf("second group target = %+v", save.Target)
+	}
+	if len(save.Scenarios) != 1 || save.Scenarios[0].ID != "c" {
+		t.Errorf("second group scenarios = %+v, want only c", save.Scenarios)
+	}
+	if len(save.Target.Methods) != 2 || len(save.allScenarios()) != 3 {
+		t.Errorf("a group must keep every method: %d methods, %d scenarios", len(save.Target.Methods), len(save.allScenarios()))
+	}
+	if !spec.NeedsSut() {
+		t.Error("NeedsSut() = false with an instance method")
+	}
+
+	spec.Target.Methods[0].Kind = KindFunction
+	if spec.NeedsSut() {
+		t.Error("NeedsSut() = true without instance methods")
+	}
+
+	single := MetaFramework{Target: TargetInfo{MethodName: "run"}, Scenarios: []Scenario{{ID: "a"}}}
+	if groups := single.Groups(); len(groups) != 1 || groups[0].Target.MethodName != "run" {
+		t.Errorf("Groups() without methods = %+v, want the spec itself", groups)
+	}
+}
+
+func TestMultipleMethodsRender(t *testing.T) {
+	spec := `{"target": {"class_name": "Repo", "methods": [
+	  {"name": "find", "scenarios": [{"id": "found", "description": "finds it"}]},
+	  {"name": "save", "scenarios": [{"id": "saved", "description": "saves it"}]}
+	]}}`
+	tests := []struct {
+		lang string
+		want []string
+	}{
+		{"go", []string{"func TestFind(t *testing.T) {", "func TestSave(t *testing.T) {", "sut.find()", "sut.save()"}},
+		{"java", []string{"class Find {", "class Save {", "void found()", "void saved()"}},
+		{"typescript", []string{"describe('find', () => {", "describe('save', () => {"}},
+		{"python", []string{"def test_find_found(self):", "def test_save_saved(self):"}},
+		{"csharp", []string{"#region Find", "#region Save", "public void Find_Found()", "public void Save_Saved()"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			assertContains(t, generate(t, spec, tt.lang, ""), tt.want...)
+		})
+	}
+}
@@ -0,0 +1,529 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// --- TIPOS PORTÁVEIS ---
+
+// Method é um método declarado de uma dependência. Os tipos são portáveis
+// ("string", "int", "list<User>", "map<string, int>", "User?") e viram os
+// tipos de cada linguagem nos templates, via Type e Params.
+type Method struct {
+	Name    string  `json:"name"`
+	Params  []Param `json:"params"`
+	Returns string  `json:"returns"` // Vazio ou "void": sem retorno
+	Async   bool    `json:"async"`   // O retorno vem embrulhado em Promise/Task/Future
+
+	// Declared é falso para métodos que só aparecem nos "mocks_setup"
+	Declared bool `json:"-"`
+	// Fails indica que algum cenário configura um erro para o método; no Go,
+	// a assinatura do mock ganha um "error" no retorno
+	Fails bool `json:"-"`
+}
+
+type Param struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Void indica um método declarado sem retorno.
+func (m Method) Void() bool {
+	return m.Declared && (m.Returns == "" || canonicalType(m.Returns) == "void")
+}
+
+// Optional indica um retorno que aceita nulo ("User?").
+func (m Method) Optional() bool {
+	t, err := parseType(m.Returns)
+	return err == nil && t.optional
+}
+
+// ErrInvalidType é devolvido (embrulhado) quando um tipo da spec não pode ser interpretado.
+var ErrInvalidType = errors.New("invalid type")
+
+// DependencyOf devolve a dependência declarada com "fieldName" (vazia se não existir).
+func (m MetaFramework) DependencyOf(fieldName string) Dependency {
+	for _, dep := range m.Dependencies {
+		if dep.FieldName == fieldName {
+			return dep
+		}
+	}
+	return Dependency{FieldName: fieldName, InterfaceName: fieldName}
+}
+
+// MethodOf devolve o método "name" da dependência; quando ele não foi
+// declarado, devolve um Method sem tipos (Declared falso).
+func (m MetaFramework) MethodOf(fieldName, name string) Method {
+	for _, method := range m.DependencyOf(fieldName).Methods {
+		if method.Name == name {
+			method.Declared = true
+			method.Fails = m.mockFails(fieldName, name)
+			return method
+		}
+	}
+	return Method{Name: name, Fails: m.mockFails(fieldName, name)}
+}
+
+// MethodsOf lista os métodos declarados da dependência seguidos dos que
+// só aparecem nos "mocks_setup".
+func (m MetaFramework) MethodsOf(fieldName string) []Method {
+	var methods []Method
+	seen := map[string]bool{}
+	for _, method := range m.DependencyOf(fieldName).Methods {
+		method.Declared = true
+		method.Fails = m.mockFails(fieldName, method.Name)
+		seen[method.Name] = true
+		methods = append(methods, method)
+	}
+	for _, name := range m.MockedMethods(fieldName) {
+		if !seen[name] {
+			methods = append(methods, Method{Name: name, Fails: m.mockFails(fieldName, name)})
+		}
+	}
+	return methods
+}
+
+// mockFails procura um erro configurado para o método em todos os cenários do
+// arquivo (inclusive de outros grupos), para a assinatura ser a mesma em todos.
+func (m MetaFramework) mockFails(fieldName, name string) bool {
+	for _, s := range m.allScenarios() {
+		for _, mock := range s.MocksSetup {
+			if mock.Dependency == fieldName && mock.Method == name && mock.Error != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AnyAsync indica um alvo ou método de dependência assíncrono, para os
+// templates incluírem os imports de corrotinas/futures só quando necessário.
+func (m MetaFramework) AnyAsync() bool {
+	if m.Target.Async {
+		return true
+	}
+	for _, dep := range m.Dependencies {
+		for _, method := range dep.Methods {
+			if method.Async {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UsesType indica se algum tipo declarado (métodos das dependências e campos
+// dos DTOs) usa "list", "map", "any" ou "optional", em qualquer nível, para os
+// templates incluírem cabeçalhos como <vector> e <optional> só quando necessário.
+func (m MetaFramework) UsesType(kind string) bool {
+	var types []string
+	for _, dep := range m.Dependencies {
+		for _, method := range dep.Methods {
+			types = append(types, method.Returns)
+			for _, p := range method.Params {
+				types = append(types, p.Type)
+			}
+		}
+	}
+	for _, def := range m.Types {
+		for _, field := range def.Fields {
+			types = append(types, field.Type)
+		}
+	}
+
+	var uses func(t typeRef) bool
+	uses = func(t typeRef) bool {
+		if t.name == kind || (kind == "optional" && t.optional) {
+			return true
+		}
+		for _, arg := range t.args {
+			if uses(arg) {
+				return true
+			}
+		}
+		return false
+	}
+	for _, s := range types {
+		if t, err := parseType(s); err == nil && uses(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParamTypes lista, sem repetição e na ordem em que aparecem, os tipos dos
+// parâmetros declarados que não são primitivos escalares (DTOs, listas e
+// mapas), já sem o "?". O mocktail do Dart exige um fallback para cada um.
+func (m MetaFramework) ParamTypes() []string {
+	var types []string
+	seen := map[string]bool{}
+	for _, dep := range m.Dependencies {
+		for _, method := range dep.Methods {
+			for _, p := range method.Params {
+				t, err := parseType(p.Type)
+				if err != nil || (primitiveTypes[t.name] && t.name != "list" && t.name != "map") {
+					continue
+				}
+				name := strings.TrimSuffix(strings.TrimSpace(p.Type), "?")
+				if !seen[name] {
+					seen[name] = true
+					types = append(types, name)
+				}
+			}
+		}
+	}
+	return types
+}
+
+// checkTypes valida todos os tipos declarados nas dependências.
+func (m MetaFramework) checkTypes() error {
+	for _, dep := range m.Dependencies {
+		for _, method := range dep.Methods {
+			types := []string{method.Returns}
+			for _, p := range method.Params {
+				types = append(types, p.Type)
+			}
+			for _, t := range types {
+				if t == "" {
+					continue
+				}
+				if _, err := parseType(t); err != nil {
+					return fmt.Errorf("%w in %s.%s: %v", ErrInvalidType, dep.FieldName, method.Name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// typeRef é um tipo portável já interpretado.
+type typeRef struct {
+	name     string // Primitivo portável ("int"), "list", "map" ou tipo da aplicação ("User")
+	args     []typeRef
+	optional bool
+}
+
+var primitiveTypes = words("string int long float double bool any void list map")
+
+var typeAliases = map[string]string{
+	"str": "string", "integer": "int", "boolean": "bool", "number": "double",
+	"object": "any", "none": "void", "unit": "void", "array": "list", "dict": "map",
+}
+
+// canonicalType devolve o nome portável de um primitivo ("Integer" -> "int")
+// ou o próprio nome, para tipos da aplicação.
+func canonicalType(name string) string {
+	lower := strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := typeAliases[lower]; ok {
+		return alias
+	}
+	if primitiveTypes[lower] {
+		return lower
+	}
+	return strings.TrimSpace(name)
+}
+
+// parseType interpreta "list<map<string, User?>>", "User[]" e afins.
+func parseType(s string) (typeRef, error) {
+	p := &typeParser{src: []rune(s)}
+	t, err := p.parse()
+	if err != nil {
+		return typeRef{}, err
+	}
+	if p.skipSpaces(); p.pos < len(p.src) {
+		return typeRef{}, fmt.Errorf("unexpected %q in type %q", string(p.src[p.pos:]), s)
+	}
+	return t, nil
+}
+
+type typeParser struct {
+	src []rune
+	pos int
+}
+
+func (p *typeParser) skipSpaces() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *typeParser) next(r rune) bool {
+	p.skipSpaces()
+	if p.pos < len(p.src) && p.src[p.pos] == r {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *typeParser) parse() (typeRef, error) {
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+			break
+		}
+		p.pos++
+	}
+	if start == p.pos {
+		return typeRef{}, fmt.Errorf("missing type name in %q", string(p.src))
+	}
+
+	t := typeRef{name: canonicalType(string(p.src[start:p.pos]))}
+	if p.next('<') {
+		for {
+			arg, err := p.parse()
+			if err != nil {
+				return typeRef{}, err
+			}
+			t.args = append(t.args, arg)
+			if p.next('>') {
+				break
+			}
+			if !p.next(',') {
+				return typeRef{}, fmt.Errorf("expected ',' or '>' in %q", string(p.src))
+			}
+		}
+	}
+
+	switch {
+	case t.name == "list" && len(t.args) != 1:
+		return typeRef{}, fmt.Errorf("list takes one type argument in %q", string(p.src))
+	case t.name == "map" && len(t.args) != 2:
+		return typeRef{}, fmt.Errorf("map takes two type arguments in %q", string(p.src))
+	case primitiveTypes[t.name] && t.name != "list" && t.name != "map" && len(t.args) > 0:
+		return typeRef{}, fmt.Errorf("%s takes no type arguments in %q", t.name, string(p.src))
+	}
+
+	// Sufixos: "User[]" e "User?"
+	for {
+		switch {
+		case p.next('['):
+			if !p.next(']') {
+				return typeRef{}, fmt.Errorf("expected ']' in %q", string(p.src))
+			}
+			t = typeRef{name: "list", args: []typeRef{t}}
+		case p.next('?'):
+			t.optional = true
+		default:
+			return t, nil
+		}
+	}
+}
+
+// --- TIPOS POR LINGUAGEM ---
+
+// typeSyntax descreve como os tipos portáveis viram tipos de uma linguagem.
+type typeSyntax struct {
+	names    map[string]string // Primitivos portáveis
+	boxed    map[string]string // Primitivos usados como argumento de tipo (ex: Integer no Java)
+	list     func(elem string) string
+	dict     func(key, value string) string
+	optional func(t string) string
+	generic  [2]string // Delimitadores dos genéricos da aplicação (ex: Page<User>)
+	param    func(name, t string) string
+}
+
+var portablePrimitives = strings.Fields("string int long float double bool any void")
+
+// primitives associa, na ordem, os primitivos portáveis aos nomes da linguagem ("-" = vazio).
+func primitives(list string) map[string]string {
+	names := map[string]string{}
+	for i, name := range strings.Fields(list) {
+		names[portablePrimitives[i]] = strings.TrimPrefix(name, "-")
+	}
+	return names
+}
+
+func wrap(format string) func(string) string {
+	return func(t string) string { return fmt.Sprintf(format, t) }
+}
+
+func wrap2(format string) func(string, string) string {
+	return func(k, v string) string { return fmt.Sprintf(format, k, v) }
+}
+
+func typeFirst(name, t string) string { return t + " " + name }
+func typeLast(name, t string) string  { return name + " " + t }
+func nameColon(name, t string) string { return name + ": " + t }
+
+var angle = [2]string{"<", ">"}
+
+var tsTypes = typeSyntax{
+	names: primitives("string number number number number boolean unknown void"),
+	list: func(elem string) string {
+		if strings.Contains(elem, " ") {
+			return "(" + elem + ")[]"
+		}
+		return elem + "[]"
+	},
+	dict: wrap2("Record<%s, %s>"), optional: wrap("%s | null"),
+	generic: angle, param: nameColon,
+}
+
+var typeSyntaxes = map[string]typeSyntax{
+	"go": {
+		names: primitives("string int int64 float32 float64 bool any -"),
+		list:  wrap("[]%s"), dict: wrap2("map[%s]%s"), optional: wrap("*%s"),
+		generic: [2]string{"[", "]"}, param: typeLast,
+	},
+	"csharp": {
+		names: primitives("string int long float double bool object void"),
+		list:  wrap("List<%s>"), dict: wrap2("Dictionary<%s, %s>"), optional: wrap("%s?"),
+		generic: angle, param: typeFirst,
+	},
+	"kotlin": {
+		names: primitives("String Int Long Float Double Boolean Any Unit"),
+		list:  wrap("List<%s>"), dict: wrap2("Map<%s, %s>"), optional: wrap("%s?"),
+		generic: angle, param: nameColon,
+	},
+	"java": {
+		names: primitives("String int long float double boolean Object void"),
+		boxed: primitives("String Integer Long Float Double Boolean Object Void"),
+		list:  wrap("List<%s>"), dict: wrap2("Map<%s, %s>"), optional: wrap("%s"),
+		generic: angle, param: typeFirst,
+	},
+	"php": {
+		names:    primitives("string int int float float bool mixed void"),
+		list:     func(string) string { return "array" },
+		dict:     func(string, string) string { return "array" },
+		optional: wrap("?%s"), generic: [2]string{"", ""},
+		param: func(name, t string) string { return t + " $" + name },
+	},
+	"typescript": tsTypes,
+	"node":       tsTypes, // Usado apenas em comentários JSDoc
+	"python": {
+		names: primitives("str int int float float bool Any None"),
+		list:  wrap("list[%s]"), dict: wrap2("dict[%s, %s]"), optional: wrap("%s | None"),
+		generic: [2]string{"[", "]"}, param: nameColon,
+	},
+	"rust": {
+		names: primitives("String i32 i64 f32 f64 bool serde_json::Value ()"),
+		list:  wrap("Vec<%s>"), dict: wrap2("HashMap<%s, %s>"), optional: wrap("Option<%s>"),
+		generic: angle, param: nameColon,
+	},
+	"swift": {
+		names: primitives("String Int Int64 Float Double Bool Any Void"),
+		list:  wrap("[%s]"), dict: wrap2("[%s: %s]"), optional: wrap("%s?"),
+		generic: angle, param: nameColon,
+	},
+	"cpp": {
+		names: primitives("std::string int int64_t float double bool std::any void"),
+		list:  wrap("std::vector<%s>"), dict: wrap2("std::map<%s, %s>"), optional: wrap("std::optional<%s>"),
+		generic: angle, param: typeFirst,
+	},
+	"dart": {
+		names: primitives("String int int double double bool dynamic void"),
+		list:  wrap("List<%s>"), dict: wrap2("Map<%s, %s>"), optional: wrap("%s?"),
+		generic: angle, param: typeFirst,
+	},
+	"scala": {
+		names: primitives("String Int Long Float Double Boolean Any Unit"),
+		list:  wrap("List[%s]"), dict: wrap2("Map[%s, %s]"), optional: wrap("Option[%s]"),
+		generic: [2]string{"[", "]"}, param: nameColon,
+	},
+}
+
+// render escreve o tipo na linguagem; "nested" indica argumento de outro tipo.
+func (ts typeSyntax) render(t typeRef, nested bool) string {
+	args := make([]string, len(t.args))
+	for i, arg := range t.args {
+		args[i] = ts.render(arg, true)
+	}
+
+	var text string
+	switch t.name {
+	case "list":
+		text = ts.list(args[0])
+	case "map":
+		text = ts.dict(args[0], args[1])
+	default:
+		if name, ok := ts.boxed[t.name]; ok && (nested || t.optional) {
+			text = name
+		} else if name, ok := ts.names[t.name]; ok {
+			text = name
+		} else {
+			text = t.name
+			if len(args) > 0 && ts.generic[0] != "" {
+				text += ts.generic[0] + strings.Join(args, ", ") + ts.generic[1]
+			}
+		}
+	}
+
+	if t.optional && t.name != "any" {
+		text = ts.optional(text)
+	}
+	return text
+}
+
+// typeName converte um tipo portável para a linguagem. Linguagens sem tipos
+// estáticos (e tipos inválidos) mantêm o texto da spec.
+func typeName(lang, s string) string {
+	ts, ok := typeSyntaxes[lang]
+	if !ok {
+		return s
+	}
+	if strings.TrimSpace(s) == "" {
+		return ts.names["void"]
+	}
+	t, err := parseType(s)
+	if err != nil {
+		return s
+	}
+	return ts.render(asciiType(lang, t), false)
+}
+
+// paramList escreve a lista de parâmetros na ordem da linguagem ("id int", "int id", "id: Int").
+func paramList(lang string, params []Param) string {
+	return paramListOf(lang, params, func(s string) string { return typeName(lang, s) })
+}
+
+// paramListOf é o paramList com a escrita dos tipos ligada à spec (ver valueSyntax.typeText).
+func paramListOf(lang string, params []Param, typeOf func(string) string) string {
+	ts, ok := typeSyntaxes[lang]
+	list := make([]string, len(params))
+	for i, p := range params {
+		name := ident(lang, p.Name)
+		if !ok {
+			list[i] = name
+			continue
+		}
+		list[i] = ts.param(name, typeOf(p.Type))
+	}
+	return strings.Join(list, ", ")
+}
+
+// javaMatcher escolhe o matcher do Mockito para um parâmetro declarado. O any()
+// devolve null, que quebra o unboxing de primitivos; any(User.class) não aceita
+// null, então tipos opcionais ficam com any().
+func javaMatcher(s string) string {
+	t, err := parseType(s)
+	if err != nil || t.optional {
+		return "any()"
+	}
+	switch t.name {
+	case "int":
+		return "anyInt()"
+	case "long":
+		return "anyLong()"
+	case "float":
+		return "anyFloat()"
+	case "double":
+		return "anyDouble()"
+	case "bool":
+		return "anyBoolean()"
+	case "string":
+		return "anyString()"
+	case "list":
+		return "anyList()"
+	case "map":
+		return "anyMap()"
+	case "any", "void":
+		return "any()"
+	}
+	return "any(" + sanitizeIdent(t.name) + ".class)"
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseType(t *testing.T) {
+	user := typeRef{name: "User"}
+	tests := []struct {
+		in   string
+		want typeRef
+	}{
+		{"int", typeRef{name: "int"}},
+		{" Integer ", typeRef{name: "int"}},
+		{"str", typeRef{name: "string"}},
+		{"User", user},
+		{"User?", typeRef{name: "User", optional: true}},
+		{"User[]", typeRef{name: "list", args: []typeRef{user}}},
+		{"User[]?", typeRef{name: "list", args: []typeRef{user}, optional: true}},
+		{"array<dict<str, number>>", typeRef{name: "list", args: []typeRef{
+			{name: "map", args: []typeRef{{name: "string"}, {name: "double"}}},
+		}}},
+		{"list<map<string, User?>>", typeRef{name: "list", args: []typeRef{
+			{name: "map", args: []typeRef{{name: "string"}, {name: "User", optional: true}}},
+		}}},
+		{"Page<User>", typeRef{name: "Page", args: []typeRef{user}}},
+		{"models.User", typeRef{name: "models.User"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseType(tt.in)
+			if err != nil {
+				t.Fatalf("parseType(%q) error: %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseType(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTypeErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"?",
+		"list",
+		"map<string>",
+		"int<string>",
+		"list<int",
+		"list<int,>",
+		"User[",
+		"User]",
+		"User Admin",
+	} {
+		t.Run(in, func(t *testing.T) {
+			if got, err := parseType(in); err == nil {
+				t.Errorf("parseType(%q) = %+v, want error", in, got)
+			}
+		})
+	}
+}
+
+func TestCheckTypes(t *testing.T) {
+	spec := MetaFramework{Dependencies: []Dependency{{
+		FieldName: "repo",
+		Methods: []Method{
+			{Name: "find", Params: []Param{{Name: "id", Type: "int"}}, Returns: "User?"},
+			{Name: "save", Params: []Param{{Name: "user", Type: "map<string>"}}},
+		},
+	}}}
+	if err := spec.checkTypes(); !errors.Is(err, ErrInvalidType) {
+		t.Errorf("checkTypes() = %v, want %v", err, ErrInvalidType)
+	}
+
+	spec.Dependencies[0].Methods = spec.Dependencies[0].Methods[:1]
+	if err := spec.checkTypes(); err != nil {
+		t.Errorf("checkTypes() = %v, want nil", err)
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	tests := []struct {
+		lang, in, want string
+	}{
+		{"go", "list<map<string, User?>>", "[]map[string]*User"},
+		{"go", "long", "int64"},
+		{"go", "", ""},
+		{"go", "list<", "list<"},
+		{"csharp", "map<string, list<int>>", "Dictionary<string, List<int>>"},
+		{"kotlin", "User?", "User?"},
+		{"kotlin", "any?", "Any"},
+		{"java", "int", "int"},
+		{"java", "int?", "Integer"},
+		{"java", "list<int>", "List<Integer>"},
+		{"php", "list<int>", "array"},
+		{"php", "User?", "?User"},
+		{"typescript", "list<int?>", "(number | null)[]"},
+		{"typescript", "map<string, bool>", "Record<string, boolean>"},
+		{"python", "map<string, User?>", "dict[str, User | None]"},
+		{"rust", "list<User?>", "Vec<Option<User>>"},
+		{"swift", "map<string, int>", "[String: Int]"},
+		{"cpp", "list<string>", "std::vector<std::string>"},
+		{"dart", "", "void"},
+		{"scala", "Page<User>", "Page[User]"},
+		{"ruby", "list<int>", "list<int>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.in, func(t *testing.T) {
+			if got := typeName(tt.lang, tt.in); got != tt.want {
+				t.Errorf("typeName(%q, %q) = %q, want %q", tt.lang, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParamList(t *testing.T) {
+	params := []Param{{Name: "user_id", Type: "int"}, {Name: "tags", Type: "list<string>"}}
+	tests := []struct {
+		lang, want string
+	}{
+		{"go", "user_id int, tags []string"},
+		{"java", "int user_id, List<String> tags"},
+		{"php", "int $user_id, array $tags"},
+		{"rust", "user_id: i32, tags: Vec<String>"},
+		{"ruby", "user_id, tags"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.lang, func(t *testing.T) {
+			if got := paramList(tt.lang, params); got != tt.want {
+				t.Errorf("paramList(%q) = %q, want %q", tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJavaMatcher(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"int", "anyInt()"},
+		{"Integer", "anyInt()"},
+		{"long", "anyLong()"},
+		{"float", "anyFloat()"},
+		{"number", "anyDouble()"},
+		{"bool", "anyBoolean()"},
+		{"string", "anyString()"},
+		{"list<User>", "anyList()"},
+		{"User[]", "anyList()"},
+		{"map<string, int>", "anyMap()"},
+		{"any", "any()"},
+		{"int?", "any()"},
+		{"User?", "any()"},
+		{"User", "any(User.class)"},
+		{"list<", "any()"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := javaMatcher(tt.in); got != tt.want {
+				t.Errorf("javaMatcher(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
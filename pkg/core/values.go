@@ -0,0 +1,405 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// --- RENDERIZAÇÃO DE VALORES ---
+
+// valueSyntax descreve como os valores JSON da spec (string, número, bool,
+// null, array e objeto) viram literais de uma linguagem.
+type valueSyntax struct {
+	null, yes, no string
+	long          string // Sufixo de inteiros fora do int32 (ex: "L" no Java)
+
+	// typedNull, quando definido, escreve null com o tipo esperado (ex: "(User)null" no C#)
+	typedNull func(t string) string
+	str       func(s string) string
+	list      func(items []string) string
+	object    func(keys, values []string) string // Chaves já ordenadas
+
+	// composite, quando definido, pode renderizar arrays e objetos inteiros
+	// (ex: Rust usa serde_json::json! para estruturas heterogêneas)
+	composite func(v interface{}) (string, bool)
+
+	// Preenchidos por funcsFor: sintaxe de DTOs, tipos da spec e convenções de nomes
+	lang   string
+	dto    dtoSyntax
+	types  map[string]TypeDef
+	naming NamingOptions
+}
+
+// quoted é a string entre aspas duplas, como o FormatValue original.
+func quoted(s string) string {
+	return `"` + s + `"`
+}
+
+var identifierRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// pairs junta chaves e valores no formato "<chave><sep><valor>".
+func pairs(keys, values []string, key func(string) string, sep string) []string {
+	out := make([]string, len(keys))
+	for i := range keys {
+		out[i] = key(keys[i]) + sep + values[i]
+	}
+	return out
+}
+
+func join(open string, items []string, close string) string {
+	return open + strings.Join(items, ", ") + close
+}
+
+// jsSyntax também serve de padrão para linguagens sem sintaxe própria.
+var jsSyntax = valueSyntax{
+	null: "null", yes: "true", no: "false", str: quoted,
+	list: func(items []string) string { return join("[", items, "]") },
+	object: func(keys, values []string) string {
+		if len(keys) == 0 {
+			return "{}"
+		}
+		key := func(k string) string {
+			if identifierRe.MatchString(k) {
+				return k
+			}
+			return quoted(k)
+		}
+		return join("{ ", pairs(keys, values, key, ": "), " }")
+	},
+}
+
+var valueSyntaxes = map[string]valueSyntax{
+	"go": {
+		null: "nil", yes: "true", no: "false", str: quoted,
+		list: func(items []string) string { return join("[]any{", items, "}") },
+		object: func(keys, values []string) string {
+			return join("map[string]any{", pairs(keys, values, quoted, ": "), "}")
+		},
+	},
+	"csharp": {
+		null: "null", yes: "true", no: "false", str: quoted,
+		// Returns(null) é ambíguo no Moq e no NSubstitute
+		typedNull: func(t string) string { return "(" + t + ")null" },
+		list:      func(items []string) string { return join("new object[] { ", items, " }") },
+		object: func(keys, values []string) string {
+			// Objetos anônimos só aceitam identificadores; senão vira Dictionary
+			for _, k := range keys {
+				if !identifierRe.MatchString(k) {
+					key := func(k string) string { return "[" + quoted(k) + "]" }
+					return join("new Dictionary<string, object> { ", pairs(keys, values, key, " = "), " }")
+				}
+			}
+			// Propriedade de objeto anônimo não pode ser um null sem tipo
+			for i := range values {
+				if values[i] == "null" {
+					values[i] = "(object)null"
+				}
+			}
+			return join("new { ", pairs(keys, values, func(k string) string { return k }, " = "), " }")
+		},
+	},
+	"kotlin": {
+		null: "null", yes: "true", no: "false", str: quoted,
+		list: func(items []string) string { return join("listOf(", items, ")") },
+		object: func(keys, values []string) string {
+			return join("mapOf(", pairs(keys, values, quoted, " to "), ")")
+		},
+	},
+	"java": {
+		null: "null", yes: "true", no: "false", long: "L", str: quoted,
+		// Arrays.asList e HashMap aceitam null, ao contrário de List.of e Map.of
+		list: func(items []string) string { return join("Arrays.asList(", items, ")") },
+		object: func(keys, values []string) string {
+			if len(keys) == 0 {
+				return "new HashMap<>()"
+			}
+			puts := make([]string, len(keys))
+			for i := range keys {
+				puts[i] = "put(" + quoted(keys[i]) + ", " + values[i] + "); "
+			}
+			return "new HashMap<>() {{ " + strings.Join(puts, "") + "}}"
+		},
+	},
+	"php": {
+		null: "null", yes: "true", no: "false", str: quoted,
+		list: func(items []string) string { return join("[", items, "]") },
+		object: func(keys, values []string) string {
+			key := func(k string) string { return stringSyntaxes["php"].literal(k, "'") }
+			return join("[", pairs(keys, values, key, " => "), "]")
+		},
+	},
+	"typescript": jsSyntax,
+	"node":       jsSyntax,
+	"python": {
+		null: "None", yes: "True", no: "False", str: quoted,
+		list: func(items []string) string { return join("[", items, "]") },
+		object: func(keys, values []string) string {
+			return join("{", pairs(keys, values, quoted, ": "), "}")
+		},
+	},
+	"rust": {
+		null: "None", yes: "true", no: "false", str: quoted,
+		composite: rustComposite,
+		list:      func(items []string) string { return join("vec![", items, "]") },
+		object: func(keys, values []string) string {
+			entries := make([]string, len(keys))
+			for i := range keys {
+				entries[i] = "(" + quoted(keys[i]) + ", " + values[i] + ")"
+			}
+			return join("HashMap::from([", entries, "])")
+		},
+	},
+	"swift": {
+		null: "nil", yes: "true", no: "false", str: quoted,
+		list: func(items []string) string { return join("[", items, "]") },
+		object: func(keys, values []string) string {
+			if len(keys) == 0 {
+				return "[:]"
+			}
+			return join("[", pairs(keys, values, quoted, ": "), "]")
+		},
+	},
+	"ruby": {
+		null: "nil", yes: "true", no: "false", str: quoted,
+		list: func(items []string) string { return join("[", items, "]") },
+		object: func(keys, values []string) string {
+			if len(keys) == 0 {
+				return "{}"
+			}
+			return join("{ ", pairs(keys, values, quoted, " => "), " }")
+		},
+	},
+	"cpp": {
+		null: "nullptr", yes: "true", no: "false", str: quoted,
+		list: func(items []string) string { return join("{", items, "}") },
+		object: func(keys, values []string) string {
+			if len(keys) == 0 {
+				return "nlohmann::json::object()" // nlohmann::json{} seria null
+			}
+			entries := make([]string, len(keys))
+			for i := range keys {
+				entries[i] = "{" + quoted(keys[i]) + ", " + values[i] + "}"
+			}
+			// Objeto sem tipo declarado: nlohmann::json, já que {{"id", 1}} sozinho não tem tipo
+			return join("nlohmann::json{", entries, "}")
+		},
+	},
+	"dart": {
+		null: "null", yes: "true", no: "false", str: quoted,
+		list: func(items []string) string { return join("[", items, "]") },
+		object: func(keys, values []string) string {
+			return join("{", pairs(keys, values, quoted, ": "), "}")
+		},
+	},
+	"elixir": {
+		null: "nil", yes: "true", no: "false", str: quoted,
+		list: func(items []string) string { return join("[", items, "]") },
+		object: func(keys, values []string) string {
+			return join("%{", pairs(keys, values, quoted, " => "), "}")
+		},
+	},
+	"scala": {
+		null: "null", yes: "true", no: "false", long: "L", str: quoted,
+		list: func(items []string) string { return join("List(", items, ")") },
+		object: func(keys, values []string) string {
+			return join("Map(", pairs(keys, values, quoted, " -> "), ")")
+		},
+	},
+}
+
+func syntaxFor(lang string) valueSyntax {
+	if syntax, ok := valueSyntaxes[lang]; ok {
+		return syntax
+	}
+	return jsSyntax
+}
+
+// format converte um valor decodificado do JSON em literal da linguagem.
+func (vs valueSyntax) format(v interface{}) string {
+	return vs.render(v, nil)
+}
+
+// render escreve o valor; "expected", quando conhecido, é o tipo portável
+// esperado (retorno declarado, campo de um DTO, item de uma lista tipada).
+func (vs valueSyntax) render(v interface{}, expected *typeRef) string {
+	if expected != nil && expected.optional && v != nil && vs.dto.some != nil {
+		inner := *expected
+		inner.optional = false
+		return vs.dto.some(vs.render(v, &inner))
+	}
+
+	switch val := v.(type) {
+	case nil:
+		return vs.null
+	case string:
+		text := vs.str(val)
+		if expected != nil && expected.name == "string" && vs.dto.owned != nil {
+			text = vs.dto.owned(text)
+		}
+		return text
+	case bool:
+		if val {
+			return vs.yes
+		}
+		return vs.no
+	case float64:
+		number := formatNumber(val)
+		integral := val == math.Trunc(val)
+		if expected != nil && vs.dto.number != nil && primitiveTypes[expected.name] {
+			return vs.dto.number(expected.name, number, integral)
+		}
+		if vs.long != "" && integral && (val > math.MaxInt32 || val < math.MinInt32) {
+			number += vs.long
+		}
+		return number
+	}
+
+	if text, ok := vs.expression(v); ok {
+		return text
+	}
+	if text, ok := vs.construct(v, expected); ok {
+		return text
+	}
+
+	if vs.composite != nil && expected == nil && !hasTypedValues(v) {
+		if text, ok := vs.composite(v); ok {
+			return text
+		}
+	}
+
+	switch val := v.(type) {
+	case []interface{}:
+		var elem *typeRef
+		if expected != nil && expected.name == "list" {
+			elem = &expected.args[0]
+		}
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = vs.render(item, elem)
+		}
+		if elem != nil && vs.dto.typedList != nil {
+			return vs.dto.typedList(vs.typeName(*elem), items)
+		}
+		return vs.list(items)
+	case map[string]interface{}:
+		var value *typeRef
+		if expected != nil && expected.name == "map" {
+			value = &expected.args[1]
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		values := make([]string, len(keys))
+		for i, k := range keys {
+			values[i] = vs.render(val[k], value)
+		}
+		if value != nil && vs.dto.typedMap != nil {
+			return vs.dto.typedMap(vs.typeName(expected.args[0]), vs.typeName(*value), keys, values)
+		}
+		return vs.object(keys, values)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// formatAs escreve o valor esperando o tipo portável "t" (ex: retorno de um método declarado).
+func (vs valueSyntax) formatAs(t string, v interface{}) string {
+	expected, err := vs.parseType(t)
+	if strings.TrimSpace(t) == "" || err != nil {
+		return vs.format(v)
+	}
+	if v == nil && vs.typedNull != nil {
+		return vs.typedNull(vs.typeName(expected))
+	}
+	// null num retorno opcional usa o "vazio" do tipo (ex: std::nullopt no C++)
+	if v == nil && expected.optional {
+		return vs.zero(expected, map[string]bool{})
+	}
+	return vs.render(v, &expected)
+}
+
+// rustComposite usa serde_json::json! para objetos e listas heterogêneas,
+// que não têm literal nativo em Rust; listas homogêneas continuam como vec![].
+func rustComposite(v interface{}) (string, bool) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+	case []interface{}:
+		homogeneous := true
+		for _, item := range val {
+			if item == nil || fmt.Sprintf("%T", item) != fmt.Sprintf("%T", val[0]) {
+				homogeneous = false
+			}
+			switch item.(type) {
+			case map[string]interface{}, []interface{}:
+				homogeneous = false
+			}
+		}
+		if homogeneous {
+			return "", false
+		}
+	default:
+		return "", false
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", false
+	}
+	return "serde_json::json!(" + string(data) + ")", true
+}
+
+// formatNumber escreve inteiros sem casas decimais e os demais no formato mais curto.
+func formatNumber(f float64) string {
+	if f == math.Trunc(f) && math.Abs(f) < 1e15 {
+		return strconv.FormatInt(int64(f), 10)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// funcsFor devolve o funcMap com as funções ligadas à linguagem do template.
+func funcsFor(lang string) template.FuncMap {
+	naming, _ := MetaInfo{}.NamingFor(lang)
+	return languageFuncs(lang, naming, nil)
+}
+
+// languageFuncs liga as funções à linguagem, às convenções de nomes e aos
+// tipos declarados na spec.
+func languageFuncs(lang string, naming NamingOptions, types []TypeDef) template.FuncMap {
+	funcs := template.FuncMap{}
+	for name, fn := range funcMap {
+		funcs[name] = fn
+	}
+
+	for name, fn := range naming.funcs() {
+		funcs[name] = fn
+	}
+
+	ss := stringSyntaxFor(lang)
+	vs := syntaxFor(lang)
+	vs.str = func(s string) string { return ss.literal(s, `"`) }
+	vs.lang, vs.dto, vs.naming = lang, dtoSyntaxes[lang], naming
+	vs.types = map[string]TypeDef{}
+	for _, def := range types {
+		vs.types[def.Name] = def
+	}
+
+	funcs["FormatValue"] = vs.format
+	funcs["FormatAs"] = vs.formatAs
+	funcs["Quote"] = func(s string) string { return ss.literal(s, ss.quote) }
+	funcs["Ident"] = func(s string) string { return ident(lang, s) }
+	funcs["Type"] = vs.typeText
+	funcs["Params"] = func(params []Param) string { return paramListOf(lang, params, vs.typeText) }
+	funcs["FieldName"] = vs.fieldName
+	funcs["DefaultOf"] = vs.defaultOf
+	funcs["ZeroOf"] = vs.zeroOf
+	funcs["VarName"] = vs.varName
+	return funcs
+}